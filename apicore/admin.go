@@ -0,0 +1,45 @@
+package apicore
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AdminAuthorizer return an error when the caller in ctx is not allowed to use the admin endpoints, usually
+// checking the principal set by the authentication middleware and interceptor. The error status code is kept
+// when it has one, PermissionDenied otherwise
+type AdminAuthorizer func(ctx context.Context) error
+
+// authorizeAdmin status error of the authorizer, every call is denied without authorizer
+func authorizeAdmin(ctx context.Context, authorize AdminAuthorizer) error {
+	if authorize == nil {
+		return status.Error(codes.PermissionDenied, "admin endpoint without authorizer")
+	}
+	err := authorize(ctx)
+	if err == nil {
+		return nil
+	}
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		return err
+	}
+	return status.Error(codes.PermissionDenied, err.Error())
+}
+
+// authorizeAdminRequest write 401 for Unauthenticated errors of the authorizer and 403 for the others,
+// false when the request is denied
+func authorizeAdminRequest(w http.ResponseWriter, r *http.Request, authorize AdminAuthorizer) bool {
+	err := authorizeAdmin(r.Context(), authorize)
+	if err == nil {
+		return true
+	}
+	s := status.Convert(err)
+	httpStatus := http.StatusForbidden
+	if s.Code() == codes.Unauthenticated {
+		httpStatus = http.StatusUnauthorized
+	}
+	http.Error(w, s.Message(), httpStatus)
+	return false
+}
@@ -0,0 +1,119 @@
+package apicore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	st "github.com/golang/protobuf/ptypes/struct"
+	"github.com/jedrp/go-core/cqrs"
+	"github.com/jedrp/go-core/infras"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IntrospectionServiceName full name of the gRPC introspection service
+const IntrospectionServiceName = "gocore.admin.Introspection"
+
+// NewIntrospectionHandler REST handler listing the executors registered on the dispatchers,
+// every request is checked by authorize, denied when nil
+func NewIntrospectionHandler(authorize AdminAuthorizer, introspectors ...infras.Introspector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorizeAdminRequest(w, r, authorize) {
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		response, err := json.Marshal(map[string]interface{}{
+			"executors": listExecutors(introspectors),
+		})
+		if err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	})
+}
+
+// IntrospectionServer gRPC introspection service, ListExecutors return the same document as the REST handler
+type IntrospectionServer interface {
+	ListExecutors(context.Context, *empty.Empty) (*st.Struct, error)
+}
+
+type introspectionServer struct {
+	authorize     AdminAuthorizer
+	introspectors []infras.Introspector
+}
+
+// NewIntrospectionServer create introspection service for the dispatchers, every call is checked by authorize, denied when nil
+func NewIntrospectionServer(authorize AdminAuthorizer, introspectors ...infras.Introspector) IntrospectionServer {
+	return &introspectionServer{authorize, introspectors}
+}
+
+func (s *introspectionServer) ListExecutors(ctx context.Context, _ *empty.Empty) (*st.Struct, error) {
+	if err := authorizeAdmin(ctx, s.authorize); err != nil {
+		return nil, err
+	}
+	// go through JSON so gRPC and REST clients get the same field names
+	b, err := json.Marshal(listExecutors(s.introspectors))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	var executors []interface{}
+	if err := json.Unmarshal(b, &executors); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &st.Struct{
+		Fields: map[string]*st.Value{
+			"executors": cqrs.ToValue(executors),
+		},
+	}, nil
+}
+
+// RegisterIntrospectionServer register the introspection service to the gRPC server
+func RegisterIntrospectionServer(s *grpc.Server, srv IntrospectionServer) {
+	s.RegisterService(&introspectionServiceDesc, srv)
+}
+
+func listExecutors(introspectors []infras.Introspector) []infras.ExecutorInfo {
+	infos := []infras.ExecutorInfo{}
+	for _, i := range introspectors {
+		infos = append(infos, i.Executors()...)
+	}
+	return infos
+}
+
+func introspectionListExecutorsHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IntrospectionServer).ListExecutors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + IntrospectionServiceName + "/ListExecutors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IntrospectionServer).ListExecutors(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var introspectionServiceDesc = grpc.ServiceDesc{
+	ServiceName: IntrospectionServiceName,
+	HandlerType: (*IntrospectionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListExecutors",
+			Handler:    introspectionListExecutorsHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
@@ -0,0 +1,75 @@
+package apicore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/jedrp/go-core/infras"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testIntrospector []infras.ExecutorInfo
+
+func (i testIntrospector) Executors() []infras.ExecutorInfo {
+	return i
+}
+
+func allowAdmin(ctx context.Context) error {
+	return nil
+}
+
+func TestIntrospection(t *testing.T) {
+	introspector := testIntrospector{
+		{TypeName: "*app.CreateUser", Kind: infras.ExecutorKindCommand, Tags: []string{"admin"}},
+	}
+
+	rec := httptest.NewRecorder()
+	NewIntrospectionHandler(allowAdmin, introspector).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/executors", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 but got %d", rec.Code)
+	}
+	var body struct {
+		Executors []infras.ExecutorInfo `json:"executors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Executors) != 1 || body.Executors[0].TypeName != "*app.CreateUser" {
+		t.Errorf("unexpected body %s", rec.Body.String())
+	}
+
+	res, err := NewIntrospectionServer(allowAdmin, introspector).ListExecutors(context.Background(), &empty.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	executors := res.Fields["executors"].GetListValue().GetValues()
+	if len(executors) != 1 {
+		t.Fatalf("expected 1 executor but got %d", len(executors))
+	}
+	kind := executors[0].GetStructValue().Fields["kind"].GetStringValue()
+	if kind != "command" {
+		t.Errorf("expected command kind but got %s", kind)
+	}
+}
+
+func TestIntrospectionAuthorization(t *testing.T) {
+	introspector := testIntrospector{{TypeName: "*app.CreateUser", Kind: infras.ExecutorKindCommand}}
+
+	rec := httptest.NewRecorder()
+	NewIntrospectionHandler(nil, introspector).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/executors", nil))
+	if rec.Code != http.StatusForbidden || strings.Contains(rec.Body.String(), "CreateUser") {
+		t.Errorf("expected 403 without authorizer but got %d %s", rec.Code, rec.Body.String())
+	}
+
+	deny := func(ctx context.Context) error { return errors.New("admins only") }
+	if _, err := NewIntrospectionServer(deny, introspector).ListExecutors(context.Background(), &empty.Empty{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied but got %v", err)
+	}
+}
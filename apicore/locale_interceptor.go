@@ -0,0 +1,58 @@
+package apicore
+
+import (
+	"context"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/jedrp/go-core/infras"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// localeMetadataKeys gRPC metadata carrying the caller locales, grpc-gateway forward the HTTP header with its prefix
+var localeMetadataKeys = []string{"accept-language", "grpcgateway-accept-language"}
+
+// UnaryServerLocaleInterceptor set the caller locales to context, localize the message of returned status
+// from infras.Messages and redact it, interceptors and handlers running inside still log the canonical message
+func UnaryServerLocaleInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = contextWithMetadataLocale(ctx)
+		resp, err := handler(ctx, req)
+		return resp, localizeError(ctx, err)
+	}
+}
+
+// StreamServerLocaleInterceptor stream version of UnaryServerLocaleInterceptor
+func StreamServerLocaleInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := contextWithMetadataLocale(stream.Context())
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = ctx
+		return localizeError(ctx, handler(srv, wrapped))
+	}
+}
+
+func contextWithMetadataLocale(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	for _, key := range localeMetadataKeys {
+		if v := md.Get(key); len(v) > 0 && v[0] != "" {
+			return infras.ContextWithLocale(ctx, infras.ParseAcceptLanguage(v[0])...)
+		}
+	}
+	return ctx
+}
+
+func localizeError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return infras.RedactStatus(infras.LocalizeStatus(ctx, s)).Err()
+}
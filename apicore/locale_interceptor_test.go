@@ -0,0 +1,34 @@
+package apicore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jedrp/go-core/infras"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerLocaleInterceptor(t *testing.T) {
+	infras.Messages.Add("fr", map[string]string{"I18N_ORDER_NOT_FOUND": "commande {id} introuvable"})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("grpcgateway-accept-language", "fr-FR,en;q=0.5"))
+
+	var handlerMessage string
+	_, err := UnaryServerLocaleInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if locales := infras.LocalesFromContext(ctx); len(locales) != 2 || locales[0] != "fr-fr" {
+			t.Errorf("unexpected locales %v", locales)
+		}
+		err := infras.Fail(codes.NotFound, "order not found").WithErrorInfo("I18N_ORDER_NOT_FOUND", "test", infras.ParamsMetadata(map[string]string{"id": "9"})).Err()
+		handlerMessage = status.Convert(err).Message()
+		return nil, err
+	})
+
+	if s := status.Convert(err); s.Message() != "commande 9 introuvable" || s.Code() != codes.NotFound {
+		t.Errorf("unexpected status %v", s.Proto())
+	}
+	if handlerMessage != "order not found" {
+		t.Errorf("handler should see the canonical message but got %s", handlerMessage)
+	}
+}
@@ -0,0 +1,212 @@
+package apicore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	st "github.com/golang/protobuf/ptypes/struct"
+	"github.com/jedrp/go-core/cqrs"
+	"github.com/jedrp/go-core/pllog"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LogLevelServiceName full name of the gRPC log level service
+const LogLevelServiceName = "gocore.admin.LogLevel"
+
+var (
+	// DefaultLevelRevert revert delay of the level changes without revert_after
+	DefaultLevelRevert = 15 * time.Minute
+	// MaxLevelRevert longer revert delays are reduced to it, a change never outlives it
+	MaxLevelRevert = 4 * time.Hour
+)
+
+// LevelChange change of the global level or of the module level, reverted after RevertAfter (e.g. 15m),
+// DefaultLevelRevert when not set and at most MaxLevelRevert
+type LevelChange struct {
+	Level       string `json:"level"`
+	Module      string `json:"module,omitempty"`
+	RevertAfter string `json:"revert_after,omitempty"`
+}
+
+// Apply the change to the levels
+func (c LevelChange) Apply(levels *pllog.LevelController) error {
+	level, err := logrus.ParseLevel(c.Level)
+	if err != nil {
+		return err
+	}
+	revertAfter := DefaultLevelRevert
+	if c.RevertAfter != "" {
+		if revertAfter, err = time.ParseDuration(c.RevertAfter); err != nil {
+			return err
+		}
+		if revertAfter <= 0 {
+			revertAfter = DefaultLevelRevert
+		}
+	}
+	if revertAfter > MaxLevelRevert {
+		revertAfter = MaxLevelRevert
+	}
+	if c.Module == "" {
+		levels.SetLevel(level, revertAfter)
+	} else {
+		levels.SetModuleLevel(c.Module, level, revertAfter)
+	}
+	return nil
+}
+
+// NewLogLevelHandler REST handler of the levels: GET list them, PUT apply a LevelChange,
+// DELETE reset the module of the module query param or all the levels without.
+// Every request is checked by authorize, denied when nil
+func NewLogLevelHandler(levels *pllog.LevelController, authorize AdminAuthorizer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorizeAdminRequest(w, r, authorize) {
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var change LevelChange
+			if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := change.Apply(levels); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		case http.MethodDelete:
+			if module := r.URL.Query().Get("module"); module != "" {
+				levels.ResetModule(module)
+			} else {
+				levels.Reset()
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		response, err := json.Marshal(levels.Snapshot())
+		if err != nil {
+			panic(err) // let the recovery middleware deal with this
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(response)
+	})
+}
+
+// LogLevelServer gRPC log level service, the requests have the fields of LevelChange and
+// the responses are the same documents as the REST handler
+type LogLevelServer interface {
+	GetLevels(context.Context, *empty.Empty) (*st.Struct, error)
+	SetLevel(context.Context, *st.Struct) (*st.Struct, error)
+	// ResetLevels reset the module of the module field or all the levels without
+	ResetLevels(context.Context, *st.Struct) (*st.Struct, error)
+}
+
+type logLevelServer struct {
+	levels    *pllog.LevelController
+	authorize AdminAuthorizer
+}
+
+// NewLogLevelServer create log level service of the controller, every call is checked by authorize, denied when nil
+func NewLogLevelServer(levels *pllog.LevelController, authorize AdminAuthorizer) LogLevelServer {
+	return &logLevelServer{levels, authorize}
+}
+
+func (s *logLevelServer) GetLevels(ctx context.Context, _ *empty.Empty) (*st.Struct, error) {
+	if err := authorizeAdmin(ctx, s.authorize); err != nil {
+		return nil, err
+	}
+	return s.snapshot()
+}
+
+func (s *logLevelServer) SetLevel(ctx context.Context, in *st.Struct) (*st.Struct, error) {
+	if err := authorizeAdmin(ctx, s.authorize); err != nil {
+		return nil, err
+	}
+	change := LevelChange{
+		Level:       in.GetFields()["level"].GetStringValue(),
+		Module:      in.GetFields()["module"].GetStringValue(),
+		RevertAfter: in.GetFields()["revert_after"].GetStringValue(),
+	}
+	if err := change.Apply(s.levels); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return s.snapshot()
+}
+
+func (s *logLevelServer) ResetLevels(ctx context.Context, in *st.Struct) (*st.Struct, error) {
+	if err := authorizeAdmin(ctx, s.authorize); err != nil {
+		return nil, err
+	}
+	if module := in.GetFields()["module"].GetStringValue(); module != "" {
+		s.levels.ResetModule(module)
+	} else {
+		s.levels.Reset()
+	}
+	return s.snapshot()
+}
+
+func (s *logLevelServer) snapshot() (*st.Struct, error) {
+	// go through JSON so gRPC and REST clients get the same field names
+	b, err := json.Marshal(s.levels.Snapshot())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return cqrs.ToValue(snapshot).GetStructValue(), nil
+}
+
+// RegisterLogLevelServer register the log level service to the gRPC server
+func RegisterLogLevelServer(s *grpc.Server, srv LogLevelServer) {
+	s.RegisterService(&logLevelServiceDesc, srv)
+}
+
+func logLevelMethodHandler(method string, newIn func() interface{}, call func(LogLevelServer, context.Context, interface{}) (*st.Struct, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := newIn()
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return call(srv.(LogLevelServer), ctx, in)
+			}
+			info := &grpc.UnaryServerInfo{
+				Server:     srv,
+				FullMethod: "/" + LogLevelServiceName + "/" + method,
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(LogLevelServer), ctx, req)
+			}
+			return interceptor(ctx, in, info, handler)
+		},
+	}
+}
+
+var logLevelServiceDesc = grpc.ServiceDesc{
+	ServiceName: LogLevelServiceName,
+	HandlerType: (*LogLevelServer)(nil),
+	Methods: []grpc.MethodDesc{
+		logLevelMethodHandler("GetLevels", func() interface{} { return new(empty.Empty) }, func(s LogLevelServer, ctx context.Context, in interface{}) (*st.Struct, error) {
+			return s.GetLevels(ctx, in.(*empty.Empty))
+		}),
+		logLevelMethodHandler("SetLevel", func() interface{} { return new(st.Struct) }, func(s LogLevelServer, ctx context.Context, in interface{}) (*st.Struct, error) {
+			return s.SetLevel(ctx, in.(*st.Struct))
+		}),
+		logLevelMethodHandler("ResetLevels", func() interface{} { return new(st.Struct) }, func(s LogLevelServer, ctx context.Context, in interface{}) (*st.Struct, error) {
+			return s.ResetLevels(ctx, in.(*st.Struct))
+		}),
+	},
+	Streams: []grpc.StreamDesc{},
+}
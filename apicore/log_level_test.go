@@ -0,0 +1,123 @@
+package apicore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	st "github.com/golang/protobuf/ptypes/struct"
+	"github.com/jedrp/go-core/pllog"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogLevelHandler(t *testing.T) {
+	levels := pllog.NewLevelController(logrus.InfoLevel)
+	handler := NewLogLevelHandler(levels, allowAdmin)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/log-levels", strings.NewReader(`{"level":"debug","module":"orders","revert_after":"15m"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 but got %d %s", rec.Code, rec.Body.String())
+	}
+	var snapshot pllog.LevelsSnapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Global != "info" || snapshot.Modules["orders"] != "debug" || snapshot.RevertAt["orders"].IsZero() {
+		t.Errorf("unexpected levels %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/log-levels", strings.NewReader(`{"level":"loud"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown level but got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/log-levels?module=orders", nil))
+	if levels.ModuleLevel("orders") != logrus.InfoLevel {
+		t.Errorf("expected the orders level reset but got %s", rec.Body.String())
+	}
+}
+
+func TestLogLevelServer(t *testing.T) {
+	levels := pllog.NewLevelController(logrus.InfoLevel)
+	srv := NewLogLevelServer(levels, allowAdmin)
+	RegisterLogLevelServer(grpc.NewServer(), srv)
+
+	in := &st.Struct{Fields: map[string]*st.Value{
+		"level": {Kind: &st.Value_StringValue{StringValue: "trace"}},
+	}}
+	res, err := srv.SetLevel(context.Background(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if global := res.Fields["global"].GetStringValue(); global != "trace" || levels.Level() != logrus.TraceLevel {
+		t.Errorf("expected trace global level but got %s", global)
+	}
+	if revertAt := levels.Snapshot().RevertAt[""]; revertAt.IsZero() || time.Until(revertAt) > DefaultLevelRevert {
+		t.Errorf("expected the change reverted after the default delay but got %v", revertAt)
+	}
+
+	in.Fields["level"] = &st.Value{Kind: &st.Value_StringValue{StringValue: "loud"}}
+	if _, err := srv.SetLevel(context.Background(), in); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument but got %v", err)
+	}
+
+	if _, err := srv.ResetLevels(context.Background(), &st.Struct{}); err != nil {
+		t.Fatal(err)
+	}
+	res, _ = srv.GetLevels(context.Background(), &empty.Empty{})
+	if global := res.Fields["global"].GetStringValue(); global != "info" {
+		t.Errorf("expected info after reset but got %s", global)
+	}
+}
+
+func TestLogLevelAuthorization(t *testing.T) {
+	levels := pllog.NewLevelController(logrus.InfoLevel)
+	deny := func(ctx context.Context) error { return errors.New("admins only") }
+
+	rec := httptest.NewRecorder()
+	NewLogLevelHandler(levels, deny).ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/log-levels", strings.NewReader(`{"level":"trace"}`)))
+	if rec.Code != http.StatusForbidden || levels.Level() != logrus.InfoLevel {
+		t.Errorf("expected 403 and the level unchanged but got %d %s", rec.Code, levels.Level())
+	}
+	rec = httptest.NewRecorder()
+	NewLogLevelHandler(levels, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/log-levels", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without authorizer but got %d", rec.Code)
+	}
+
+	unauthenticated := func(ctx context.Context) error { return status.Error(codes.Unauthenticated, "missing token") }
+	in := &st.Struct{Fields: map[string]*st.Value{
+		"level": {Kind: &st.Value_StringValue{StringValue: "trace"}},
+	}}
+	if _, err := NewLogLevelServer(levels, unauthenticated).SetLevel(context.Background(), in); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated but got %v", err)
+	}
+	if _, err := NewLogLevelServer(levels, deny).ResetLevels(context.Background(), &st.Struct{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied but got %v", err)
+	}
+	if levels.Level() != logrus.InfoLevel {
+		t.Errorf("expected the level unchanged but got %s", levels.Level())
+	}
+}
+
+func TestLevelChangeRevertCap(t *testing.T) {
+	levels := pllog.NewLevelController(logrus.InfoLevel)
+	if err := (LevelChange{Level: "trace", RevertAfter: "720h"}).Apply(levels); err != nil {
+		t.Fatal(err)
+	}
+	if revertAt := levels.Snapshot().RevertAt[""]; time.Until(revertAt) > MaxLevelRevert {
+		t.Errorf("expected the revert capped to %s but got %v", MaxLevelRevert, revertAt)
+	}
+}
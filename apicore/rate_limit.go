@@ -0,0 +1,78 @@
+package apicore
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jedrp/go-core/infras"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// GRPCRateLimitKeyFunc select the bucket of a gRPC call
+type GRPCRateLimitKeyFunc func(ctx context.Context, fullMethod string) string
+
+// HTTPRateLimitKeyFunc select the bucket of a REST request
+type HTTPRateLimitKeyFunc func(r *http.Request) string
+
+// MethodAndCallerKey one bucket per method and caller
+func MethodAndCallerKey(ctx context.Context, fullMethod string) string {
+	return fullMethod + "|" + infras.PrincipalFromContext(ctx)
+}
+
+// PathAndCallerKey one bucket per path and caller
+func PathAndCallerKey(r *http.Request) string {
+	return r.Method + " " + r.URL.Path + "|" + infras.PrincipalFromContext(r.Context())
+}
+
+// UnaryServerRateLimitInterceptor refuse calls over the limit with ResourceExhausted, keyFunc default to MethodAndCallerKey
+func UnaryServerRateLimitInterceptor(limiter *infras.RateLimiter, keyFunc GRPCRateLimitKeyFunc) grpc.UnaryServerInterceptor {
+	if keyFunc == nil {
+		keyFunc = MethodAndCallerKey
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if ok, wait := limiter.Allow(keyFunc(ctx, info.FullMethod)); !ok {
+			return nil, rateLimitError(wait)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRateLimitInterceptor refuse streams over the limit with ResourceExhausted, keyFunc default to MethodAndCallerKey
+func StreamServerRateLimitInterceptor(limiter *infras.RateLimiter, keyFunc GRPCRateLimitKeyFunc) grpc.StreamServerInterceptor {
+	if keyFunc == nil {
+		keyFunc = MethodAndCallerKey
+	}
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if ok, wait := limiter.Allow(keyFunc(stream.Context(), info.FullMethod)); !ok {
+			return rateLimitError(wait)
+		}
+		return handler(srv, stream)
+	}
+}
+
+// RateLimitMiddleware answer 429 with Retry-After to requests over the limit, keyFunc default to PathAndCallerKey
+func RateLimitMiddleware(handler http.Handler, limiter *infras.RateLimiter, keyFunc HTTPRateLimitKeyFunc) http.Handler {
+	if keyFunc == nil {
+		keyFunc = PathAndCallerKey
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := limiter.Allow(keyFunc(r)); !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			w.WriteHeader(http.StatusTooManyRequests)
+			response, _ := json.Marshal(map[string]string{"message": "Too many requests"})
+			w.Write(response)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func rateLimitError(wait time.Duration) error {
+	return infras.FailWithRetryDelay(codes.ResourceExhausted, "rate limit exceeded", wait).Error.Err()
+}
@@ -0,0 +1,30 @@
+package apicore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jedrp/go-core/infras"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	handler := RateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), infras.NewRateLimiter(0.5, 1), nil)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/products", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 but got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/products", nil))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 but got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "2" {
+		t.Errorf("expected Retry-After 2 but got %s", rec.Header().Get("Retry-After"))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"runtime/debug"
 
+	"github.com/jedrp/go-core/infras"
 	"github.com/jedrp/go-core/pllog"
 	uuid "github.com/satori/go.uuid"
 )
@@ -15,6 +16,10 @@ func HandlePanicMiddleware(handler http.Handler, logger pllog.PlLogger) http.Han
 		ctx := NewRequestContext(r)
 		defer func() {
 			if rErr := recover(); rErr != nil {
+				if rErr == http.ErrAbortHandler {
+					// aborted response, e.g. failing stream, let net/http close the connection
+					panic(rErr)
+				}
 				if logger != nil {
 					pllog.CreateLogEntryFromContext(ctx, logger).Error(rErr, string(debug.Stack()))
 				}
@@ -24,7 +29,15 @@ func HandlePanicMiddleware(handler http.Handler, logger pllog.PlLogger) http.Han
 				w.Write(response)
 			}
 		}()
-		handler.ServeHTTP(w, r.WithContext(ctx))
+		r = r.WithContext(ctx)
+		handler.ServeHTTP(infras.WithRequest(w, r), r)
+	})
+}
+
+// HTTPStatusMiddleware answer the codes of the overrides with their HTTP status, the other services keep the default mapping
+func HTTPStatusMiddleware(handler http.Handler, overrides infras.HTTPStatusOverrides) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(infras.ContextWithHTTPStatus(r.Context(), overrides)))
 	})
 }
 
@@ -41,5 +54,9 @@ func NewRequestContext(r *http.Request) context.Context {
 	if corID != "" {
 		ctx = context.WithValue(ctx, pllog.CorrelationID, corID)
 	}
+
+	if lang := r.Header.Get("Accept-Language"); lang != "" {
+		ctx = infras.ContextWithLocale(ctx, infras.ParseAcceptLanguage(lang)...)
+	}
 	return ctx
 }
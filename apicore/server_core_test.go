@@ -4,12 +4,16 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 
+	"github.com/go-openapi/runtime"
 	"github.com/go-openapi/swag"
+	"github.com/jedrp/go-core/infras"
 	"github.com/jedrp/go-core/pllog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 type emptyServiceServer interface{}
@@ -95,3 +99,17 @@ func (s *testRestServer) GetCommandLineOptionsGroups() []swag.CommandLineOptions
 func (s *testRestServer) Serve() (err error) {
 	return nil
 }
+
+func TestCoreServerV2SetHTTPStatus(t *testing.T) {
+	app := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		infras.Fail(codes.Aborted, "concurrent update").WriteResponse(w, runtime.JSONProducer())
+	})
+	s := NewCoreServerV2(context.Background(), &pllog.DefaultLogger{}, app, nil)
+	s.SetHTTPStatus(codes.Aborted, http.StatusConflict)
+
+	rec := httptest.NewRecorder()
+	s.restHandler.ServeHTTP(rec, httptest.NewRequest("PUT", "/orders/1", nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected the server override 409 but got %d", rec.Code)
+	}
+}
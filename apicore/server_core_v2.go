@@ -19,6 +19,7 @@ import (
 	strfmt "github.com/go-openapi/strfmt"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	"github.com/jedrp/go-core/infras"
 	"github.com/jedrp/go-core/pllog"
 	flags "github.com/jessevdk/go-flags"
 	"golang.org/x/net/http2"
@@ -26,6 +27,7 @@ import (
 	"golang.org/x/net/netutil"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
 )
@@ -44,9 +46,13 @@ type CoreServerV2 struct {
 	CleanupTimeout    time.Duration    `long:"cleanup-timeout" description:"grace period for which to wait before killing idle connections" default:"10s"`
 	GracefulTimeout   time.Duration    `long:"graceful-timeout" description:"grace period for which to wait before shutting down the server" default:"15s"`
 	MaxHeaderSize     flagext.ByteSize `long:"max-header-size" description:"controls the maximum number of bytes the server will read parsing the request header's keys and values, including the request line. It does not limit the size of the request body." default:"1MiB"`
+	PageTokenKey      string           `long:"page-token-key" description:"key signing the page tokens, shared by the instances of the service" env:"PAGE_TOKEN_KEY"`
 
 	listenScheme     string
 	restHandler      http.Handler
+	appHandler       http.Handler
+	mountedHandlers  *http.ServeMux
+	httpStatuses     infras.HTTPStatusOverrides
 	logger           pllog.PlLogger
 	grpcServer       *grpc.Server
 	restServer       *http.Server
@@ -62,13 +68,19 @@ func NewCoreServerV2(ctx context.Context,
 	coreServer := &CoreServerV2{
 		logger:       logger,
 		listenScheme: schemeHTTP,
+		httpStatuses: infras.HTTPStatusOverrides{},
 	}
 
 	parser := flags.NewParser(coreServer, flags.IgnoreUnknown)
 	ParseConfig(parser)
+	if coreServer.PageTokenKey != "" {
+		infras.PageTokens = infras.NewPageTokenCodec([]byte(coreServer.PageTokenKey))
+	}
 
 	// set up REST server
-	coreServer.restHandler = HandlePanicMiddleware(restHandler, logger)
+	coreServer.appHandler = restHandler
+	coreServer.mountedHandlers = http.NewServeMux()
+	coreServer.restHandler = HTTPStatusMiddleware(HandlePanicMiddleware(http.HandlerFunc(coreServer.serveMountedOrApp), logger), coreServer.httpStatuses)
 
 	// set up gRPC server
 	formats := strfmt.Default
@@ -79,10 +91,12 @@ func NewCoreServerV2(ctx context.Context,
 	}),
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			UnaryServerRequestContextInterceptor(),
+			UnaryServerLocaleInterceptor(),
 			UnaryServerPanicInterceptor(logger),
 			UnaryValidatorServerInterceptor(formats, logger),
 		)), grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
 			StreamServerRequestInterceptor(),
+			StreamServerLocaleInterceptor(),
 			grpc_recovery.StreamServerInterceptor(
 				grpc_recovery.WithRecoveryHandlerContext(getRecoveryHandlerFuncContextHandler(logger)),
 			),
@@ -113,6 +127,7 @@ func (s *CoreServerV2) StartServing(ctx context.Context) error {
 	if s.GRPCPort < 1 && s.RESTPort < 1 {
 		s.logger.Panicf("GRPC_PORT and REST_PORT are both not configured, stop!")
 	}
+	defer s.closeLogger()
 
 	if s.GRPCPort > 1 && s.RESTPort < 1 {
 		// enable only grp
@@ -160,6 +175,66 @@ func (s *CoreServerV2) StartServing(ctx context.Context) error {
 	return nil
 }
 
+// closeLogger ship the entries buffered by the logger before the process exits
+func (s *CoreServerV2) closeLogger() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := pllog.Close(ctx, s.logger); err != nil {
+		s.logger.Warnf("closing the logger failed: %v", err)
+	}
+}
+
+// SetHTTPStatus answer the code with the HTTP status on the REST routes of the server, must be called before StartServing
+func (s *CoreServerV2) SetHTTPStatus(code codes.Code, httpStatus int) {
+	s.httpStatuses[code] = httpStatus
+}
+
+// MountRESTHandler serve the handler for the pattern instead of the application REST handler, must be called before StartServing
+func (s *CoreServerV2) MountRESTHandler(pattern string, handler http.Handler) {
+	s.mountedHandlers.Handle(pattern, handler)
+}
+
+// RegisterGRPCService register an additional service to the gRPC server, must be called before StartServing
+func (s *CoreServerV2) RegisterGRPCService(desc *grpc.ServiceDesc, impl interface{}) {
+	s.grpcServer.RegisterService(desc, impl)
+}
+
+// MountIntrospection expose the dispatchers registered executors on the REST path and as gRPC introspection service,
+// the calls are checked by authorize since they share the public listeners. Ignored with a warning without authorizer
+func (s *CoreServerV2) MountIntrospection(path string, authorize AdminAuthorizer, introspectors ...infras.Introspector) {
+	if authorize == nil {
+		s.logger.Warnf("introspection not mounted on %s, an authorizer is required", path)
+		return
+	}
+	s.MountRESTHandler(path, NewIntrospectionHandler(authorize, introspectors...))
+	RegisterIntrospectionServer(s.grpcServer, NewIntrospectionServer(authorize, introspectors...))
+}
+
+// MountLogLevels expose the runtime levels of the logger on the REST path and as gRPC log level service,
+// the calls are checked by authorize since they share the public listeners. Ignored with a warning when
+// the logger levels are fixed or without authorizer
+func (s *CoreServerV2) MountLogLevels(path string, authorize AdminAuthorizer) {
+	if authorize == nil {
+		s.logger.Warnf("log levels not mounted on %s, an authorizer is required", path)
+		return
+	}
+	levels := pllog.LevelsOf(s.logger)
+	if levels == nil {
+		s.logger.Warnf("log levels not mounted on %s, the logger has no level controller", path)
+		return
+	}
+	s.MountRESTHandler(path, NewLogLevelHandler(levels, authorize))
+	RegisterLogLevelServer(s.grpcServer, NewLogLevelServer(levels, authorize))
+}
+
+func (s *CoreServerV2) serveMountedOrApp(w http.ResponseWriter, r *http.Request) {
+	if h, pattern := s.mountedHandlers.Handler(r); pattern != "" {
+		h.ServeHTTP(w, r)
+		return
+	}
+	s.appHandler.ServeHTTP(w, r)
+}
+
 func (s *CoreServerV2) getHandlerFunc() http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.ProtoMajor == 2 && strings.Contains(r.Header.Get("Content-Type"), "application/grpc") {
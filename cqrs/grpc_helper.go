@@ -6,27 +6,18 @@ import (
 	"reflect"
 	"strconv"
 
+	"github.com/jedrp/go-core/infras"
 	"github.com/jedrp/go-core/plresult"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/jedrp/go-core/redact"
 
 	st "github.com/golang/protobuf/ptypes/struct"
 )
 
+// GetgRPCError gRPC status error of the plresult error
+//
+// Deprecated: use plresult.GetGrpcError, the authoritative mapping keeping the error code
 func GetgRPCError(err plresult.Error) error {
-	if err != nil {
-		var code codes.Code
-		switch err.(type) {
-		case *plresult.ValidationError:
-			code = codes.InvalidArgument
-		case *plresult.NotFoundError:
-			code = codes.NotFound
-		default:
-			code = codes.Internal
-		}
-		return status.Errorf(code, err.GetErrorMessage())
-	}
-	return nil
+	return plresult.GetGrpcError(err)
 }
 
 // ToStruct converts a map[string]interface{} to a ptypes.Struct
@@ -44,7 +35,8 @@ func ToStruct(v map[string]interface{}) *st.Struct {
 	}
 }
 
-// ToValue converts an interface{} to a ptypes.Value
+// ToValue converts an interface{} to a ptypes.Value, struct fields tagged `redact:"true"` are masked and
+// error messages redacted, other values are kept as the REST responses do
 func ToValue(v interface{}) *st.Value {
 	switch v := v.(type) {
 	case nil:
@@ -138,15 +130,37 @@ func ToValue(v interface{}) *st.Value {
 	case error:
 		return &st.Value{
 			Kind: &st.Value_StringValue{
-				StringValue: v.Error(),
+				StringValue: redact.String(v.Error()),
 			},
 		}
+	case infras.Paged:
+		return pageValue(v)
 	default:
 		// Fallback to reflection for other types
 		return toValue(reflect.ValueOf(v))
 	}
 }
 
+// pageValue page with the field names of the REST responses, items, next_page_token and total_count
+func pageValue(p infras.Paged) *st.Value {
+	fields := map[string]*st.Value{
+		"items": toValue(reflect.ValueOf(p.PageItems())),
+	}
+	if next := p.NextPage(); next != "" {
+		fields["next_page_token"] = ToValue(next)
+	}
+	if total, ok := p.PageTotalCount(); ok {
+		fields["total_count"] = ToValue(total)
+	}
+	return &st.Value{
+		Kind: &st.Value_StructValue{
+			StructValue: &st.Struct{
+				Fields: fields,
+			},
+		},
+	}
+}
+
 func toValue(v reflect.Value) *st.Value {
 	switch v.Kind() {
 	case reflect.Bool:
@@ -178,6 +192,12 @@ func toValue(v reflect.Value) *st.Value {
 			return nil
 		}
 		return toValue(reflect.Indirect(v))
+	case reflect.Interface:
+		// elements of []interface{} and map[string]interface{}
+		if v.IsNil() {
+			return nil
+		}
+		return ToValue(v.Interface())
 	case reflect.Array, reflect.Slice:
 		size := v.Len()
 		if size == 0 {
@@ -205,7 +225,11 @@ func toValue(v reflect.Value) *st.Value {
 			name := t.Field(i).Name
 			// Better way?
 			if len(name) > 0 && 'A' <= name[0] && name[0] <= 'Z' {
-				fields[name] = toValue(v.Field(i))
+				if redact.IsTagged(t.Field(i)) {
+					fields[name] = ToValue(redact.Mask)
+				} else {
+					fields[name] = toValue(v.Field(i))
+				}
 			}
 		}
 		if len(fields) == 0 {
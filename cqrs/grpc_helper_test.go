@@ -8,6 +8,7 @@ import (
 	"github.com/go-openapi/swag"
 	st "github.com/golang/protobuf/ptypes/struct"
 	"github.com/jedrp/go-core/cqrs"
+	"github.com/jedrp/go-core/infras"
 )
 
 func TestToStruct(t *testing.T) {
@@ -86,3 +87,34 @@ type Routing struct {
 	// Required: true
 	Topic *string `json:"topic"`
 }
+
+func TestToValueRedacted(t *testing.T) {
+	v := cqrs.ToValue(struct {
+		Name  string
+		Email string
+		Token string
+		Key   string `redact:"true"`
+	}{"jed", "jed@example.com", "t0k", "s3cret"})
+	fields := v.GetStructValue().Fields
+	if fields["Name"].GetStringValue() != "jed" || fields["Key"].GetStringValue() != "[REDACTED]" {
+		t.Errorf("unexpected struct %v", fields)
+	}
+	// results are the same as over REST, only the tagged fields are masked
+	if fields["Email"].GetStringValue() != "jed@example.com" || fields["Token"].GetStringValue() != "t0k" {
+		t.Errorf("expected the untagged fields kept but got %v", fields)
+	}
+}
+
+func TestToValuePage(t *testing.T) {
+	tokens := infras.PageTokens
+	infras.PageTokens = infras.NewPageTokenCodec([]byte("secret"))
+	defer func() { infras.PageTokens = tokens }()
+	page, err := infras.NewPage([]string{"a", "b"}, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := cqrs.ToValue(page.WithTotalCount(3)).GetStructValue().Fields
+	if len(fields["items"].GetListValue().Values) != 2 || fields["next_page_token"].GetStringValue() != page.NextPageToken || fields["total_count"].GetNumberValue() != 3 {
+		t.Errorf("expected the REST field names but got %v", fields)
+	}
+}
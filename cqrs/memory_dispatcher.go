@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
+	"github.com/jedrp/go-core/infras"
 	"github.com/jedrp/go-core/pllog"
 	"github.com/jedrp/go-core/plresult"
 )
@@ -13,6 +15,8 @@ import (
 // InMemoryDispatcher ...
 type InMemoryDispatcher struct {
 	handlers map[string]IHandler
+	infos    map[string]infras.ExecutorInfo
+	stats    *infras.ExecutorStatsCollector
 	logger   pllog.PlLogger
 }
 
@@ -20,6 +24,8 @@ type InMemoryDispatcher struct {
 func NewInMemoryDispatcher(loggerImpl pllog.PlLogger) *InMemoryDispatcher {
 	b := &InMemoryDispatcher{
 		handlers: make(map[string]IHandler),
+		infos:    make(map[string]infras.ExecutorInfo),
+		stats:    infras.NewExecutorStatsCollector(),
 		logger:   loggerImpl,
 	}
 	return b
@@ -34,6 +40,7 @@ func (d *InMemoryDispatcher) RegisterHandler(ctx context.Context, handler IHandl
 			return fmt.Errorf("duplicate command handler registration with command bus for command of type: %s", typeName)
 		}
 		d.handlers[typeName] = handler
+		d.infos[typeName] = newExecutorInfo(typeName, command)
 	}
 	return nil
 }
@@ -42,7 +49,9 @@ func (d *InMemoryDispatcher) RegisterHandler(ctx context.Context, handler IHandl
 func (d *InMemoryDispatcher) Dispatch(ctx context.Context, command interface{}) *plresult.Result {
 	typeName := reflect.TypeOf(command).String()
 	if handler, ok := d.handlers[typeName]; ok {
+		start := time.Now()
 		result := handler.Handle(ctx, command)
+		d.stats.Record(typeName, time.Since(start), !result.IsSuccess)
 		if d.logger != nil && !result.IsSuccess && result.Error != nil {
 			pllog.CreateLogEntryFromContext(ctx, d.logger).Error(result.Error.GetOriginError().Error())
 		}
@@ -50,3 +59,27 @@ func (d *InMemoryDispatcher) Dispatch(ctx context.Context, command interface{})
 	}
 	return plresult.InternalErrorResult(errors.New("InMemoryDispatcher can't find handler"), "MISING_HANDLER_IMPL")
 }
+
+// Executors list registered commands and queries with their dispatch statistics
+func (d *InMemoryDispatcher) Executors() []infras.ExecutorInfo {
+	infos := make([]infras.ExecutorInfo, 0, len(d.infos))
+	for typeName, info := range d.infos {
+		info.Stats = d.stats.Get(typeName)
+		infos = append(infos, info)
+	}
+	infras.SortExecutorInfos(infos)
+	return infos
+}
+
+// newExecutorInfo IQuery is satisfied by any value, so everything that is not an ICommand is listed as query
+func newExecutorInfo(typeName string, command interface{}) infras.ExecutorInfo {
+	info := infras.ExecutorInfo{
+		TypeName: typeName,
+		Kind:     infras.ExecutorKindQuery,
+		Tags:     []string{},
+	}
+	if _, ok := command.(ICommand); ok {
+		info.Kind = infras.ExecutorKindCommand
+	}
+	return info
+}
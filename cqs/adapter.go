@@ -0,0 +1,80 @@
+package cqs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jedrp/go-core/cqrs"
+	"github.com/jedrp/go-core/infras"
+	"github.com/jedrp/go-core/plresult"
+	"google.golang.org/grpc/codes"
+)
+
+// HandlerCommand run a cqrs.IHandler command from MemoryDispatcher, the handler is registered as dependences:
+//
+//	d.Register(ctx, handler, &cqs.HandlerCommand[*CreateUser]{})
+//	d.Dispatch(ctx, &cqs.HandlerCommand[*CreateUser]{Command: cmd})
+type HandlerCommand[C any] struct {
+	Command C
+	Tags    []string
+	handler cqrs.IHandler
+}
+
+func (c *HandlerCommand[C]) SetDependences(ctx context.Context, deps interface{}) {
+	c.handler, _ = deps.(cqrs.IHandler)
+}
+
+func (c *HandlerCommand[C]) Execute(ctx context.Context) *infras.Result {
+	return handle(ctx, c.handler, c.Command)
+}
+
+func (c *HandlerCommand[C]) IsCommand() []string {
+	return c.Tags
+}
+
+// HandlerQuery run a cqrs.IHandler query from MemoryDispatcher, see HandlerCommand
+type HandlerQuery[Q any] struct {
+	Query   Q
+	Tags    []string
+	handler cqrs.IHandler
+}
+
+func (q *HandlerQuery[Q]) SetDependences(ctx context.Context, deps interface{}) {
+	q.handler, _ = deps.(cqrs.IHandler)
+}
+
+func (q *HandlerQuery[Q]) Execute(ctx context.Context) *infras.Result {
+	return handle(ctx, q.handler, q.Query)
+}
+
+func (q *HandlerQuery[Q]) IsQuery() []string {
+	return q.Tags
+}
+
+func handle(ctx context.Context, handler cqrs.IHandler, command interface{}) *infras.Result {
+	if handler == nil {
+		return infras.Failf(codes.Internal, "%T registered without cqrs.IHandler dependences", command)
+	}
+	return plresult.ToInfras(handler.Handle(ctx, command))
+}
+
+// executorHandler run cqs executors from cqrs.InMemoryDispatcher
+type executorHandler struct {
+	deps interface{}
+}
+
+// NewExecutorHandler cqrs.IHandler executing cqs Executors with the dependences:
+//
+//	d.RegisterHandler(ctx, cqs.NewExecutorHandler(deps), &GetUser{})
+func NewExecutorHandler(deps interface{}) cqrs.IHandler {
+	return &executorHandler{deps}
+}
+
+func (h *executorHandler) Handle(ctx context.Context, command interface{}) *plresult.Result {
+	e, ok := command.(Executor)
+	if !ok {
+		return plresult.InternalErrorResult(fmt.Errorf("%T is not a cqs.Executor", command), "MISING_HANDLER_IMPL")
+	}
+	e.SetDependences(ctx, h.deps)
+	return plresult.FromInfras(e.Execute(ctx))
+}
@@ -0,0 +1,70 @@
+package cqs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jedrp/go-core/cqrs"
+	"github.com/jedrp/go-core/cqs"
+	"github.com/jedrp/go-core/infras"
+	"github.com/jedrp/go-core/pllog"
+	"github.com/jedrp/go-core/plresult"
+	"google.golang.org/grpc/codes"
+)
+
+type createUser struct{ Name string }
+
+type createUserHandler struct{}
+
+func (*createUserHandler) Handle(ctx context.Context, command interface{}) *plresult.Result {
+	if command.(*createUser).Name == "" {
+		return plresult.ValidationErrorResult(errors.New("name is empty"), "NAME_REQUIRED", "Name is required")
+	}
+	return plresult.OKResult("created")
+}
+
+func TestHandlerOnMemoryDispatcher(t *testing.T) {
+	d := cqs.NewMemoryDispatcher(&pllog.DefaultLogger{}, 0)
+	ctx := context.Background()
+	d.Register(ctx, &createUserHandler{}, &cqs.HandlerCommand[*createUser]{})
+
+	r := d.Dispatch(ctx, &cqs.HandlerCommand[*createUser]{Command: &createUser{Name: "jed"}})
+	if r.Error != nil || r.Value != "created" {
+		t.Errorf("unexpected result %+v", r)
+	}
+
+	r = d.Dispatch(ctx, &cqs.HandlerCommand[*createUser]{Command: &createUser{}})
+	if r.Error.Code() != codes.InvalidArgument || r.ErrorInfo().Type != "NAME_REQUIRED" {
+		t.Errorf("unexpected failure %v", r.Error)
+	}
+	// back to plresult without loss
+	pr := plresult.FromInfras(r)
+	if _, ok := pr.Error.(*plresult.ValidationError); !ok {
+		t.Errorf("expected ValidationError but got %T", pr.Error)
+	}
+	if pr.Error.GetCode() != "NAME_REQUIRED" || pr.Error.GetErrorMessage() != "Name is required" || pr.Error.GetOriginError().Error() != "name is empty" {
+		t.Errorf("conversion lost data %+v", pr.Error)
+	}
+}
+
+func TestExecutorOnInMemoryDispatcher(t *testing.T) {
+	d := cqrs.NewInMemoryDispatcher(nil)
+	ctx := context.Background()
+	d.RegisterHandler(ctx, cqs.NewExecutorHandler(&testDeps{}), &testCommand{}, &testQuery{})
+
+	r := d.Dispatch(ctx, &testCommand{})
+	if !r.IsSuccess || r.Value != 1 {
+		t.Errorf("unexpected result %+v", r)
+	}
+	r = d.Dispatch(ctx, &testQuery{})
+	if _, ok := r.Error.(*plresult.InternalServerError); !ok || r.Error.GetErrorMessage() != "test internal" {
+		t.Errorf("unexpected failure %+v", r.Error)
+	}
+	if code := plresult.CodeOf(plresult.NewErrorFromCode(codes.AlreadyExists, errors.New("dup"))); code != codes.AlreadyExists {
+		t.Errorf("expected AlreadyExists but got %v", code)
+	}
+	if infras.HTTPStatusFromCode(plresult.CodeOf(r.Error)) != 500 {
+		t.Error("internal error should be 500")
+	}
+}
@@ -0,0 +1,86 @@
+package cqs
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/jedrp/go-core/infras"
+	"github.com/jedrp/go-core/pllog"
+	"github.com/jedrp/go-core/redact"
+	"google.golang.org/grpc/codes"
+)
+
+// DefaultAuditRedactedFields payload fields never written to the audit log, compared case insensitive
+var DefaultAuditRedactedFields = []string{"password", "secret", "token", "authorization", "apikey", "creditcard"}
+
+// AuditRecord who did what and when, with the outcome of the command
+type AuditRecord struct {
+	Time          time.Time       `json:"time"`
+	Principal     string          `json:"principal"`
+	CommandType   string          `json:"commandType"`
+	Payload       json.RawMessage `json:"payload"`
+	RequestID     string          `json:"requestId,omitempty"`
+	CorrelationID string          `json:"correlationId,omitempty"`
+	Code          string          `json:"code"`
+	DurationMs    float64         `json:"durationMs"`
+	// PrevHash and Hash are filled by sinks chaining the records
+	PrevHash string `json:"prevHash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+// AuditSink store audit records
+type AuditSink interface {
+	Write(ctx context.Context, r *AuditRecord) error
+}
+
+// AuditBehavior record every executed Command to the sink, Queries are not audited.
+// The payload is redacted by redact.Default and the redactedFields, DefaultAuditRedactedFields when not provided.
+func AuditBehavior(sink AuditSink, logger pllog.PlLogger, redactedFields ...string) Behavior {
+	if len(redactedFields) == 0 {
+		redactedFields = DefaultAuditRedactedFields
+	}
+	redactor := redact.Default.WithFields(redactedFields...)
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, e Executor) *infras.Result {
+			if _, ok := e.(Command); !ok {
+				return next(ctx, e)
+			}
+			// take the payload before dependences are set to the executor
+			start := time.Now()
+			record := &AuditRecord{
+				Time:        start.UTC(),
+				Principal:   infras.PrincipalFromContext(ctx),
+				CommandType: reflect.TypeOf(e).String(),
+				Payload:     redactedPayload(e, redactor),
+			}
+			if v, ok := ctx.Value(pllog.RequestID).(string); ok {
+				record.RequestID = v
+			}
+			if v, ok := ctx.Value(pllog.CorrelationID).(string); ok {
+				record.CorrelationID = v
+			}
+
+			r := next(ctx, e)
+			record.DurationMs = float64(time.Since(start)) / float64(time.Millisecond)
+			record.Code = codes.OK.String()
+			if r != nil && r.Error != nil {
+				record.Code = r.Error.Code().String()
+			}
+
+			if err := sink.Write(ctx, record); err != nil {
+				pllog.CreateLogEntryFromContext(ctx, logger).Errorf("Fail to write audit record of %s: %v", record.CommandType, err)
+			}
+			return r
+		}
+	}
+}
+
+func redactedPayload(e Executor, redactor *redact.Redactor) json.RawMessage {
+	b, err := json.Marshal(redactor.Value(e))
+	if err != nil {
+		b, _ = json.Marshal(map[string]string{"error": "payload is not serializable: " + err.Error()})
+	}
+	return b
+}
@@ -0,0 +1,157 @@
+package cqs
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// MemoryAuditSink keep records in memory, for tests
+type MemoryAuditSink struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+func NewMemoryAuditSink() *MemoryAuditSink {
+	return &MemoryAuditSink{}
+}
+
+func (s *MemoryAuditSink) Write(ctx context.Context, r *AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, *r)
+	return nil
+}
+
+// Records return a copy of the written records
+func (s *MemoryAuditSink) Records() []AuditRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]AuditRecord(nil), s.records...)
+}
+
+// FileAuditSink append records as JSON lines, every record carry the hash of the previous one
+// so removing or editing a line breaks the chain, see VerifyAuditLog
+type FileAuditSink struct {
+	mu       sync.Mutex
+	file     *os.File
+	lastHash string
+}
+
+// NewFileAuditSink open or create the audit file and continue its hash chain
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	lastHash, err := verifyAuditChain(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("audit file %s is corrupted: %v", path, err)
+	}
+	return &FileAuditSink{
+		file:     f,
+		lastHash: lastHash,
+	}, nil
+}
+
+func (s *FileAuditSink) Write(ctx context.Context, r *AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash, err := hashAuditRecord(s.lastHash, r)
+	if err != nil {
+		return err
+	}
+	r.PrevHash = s.lastHash
+	r.Hash = hash
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	s.lastHash = hash
+	return nil
+}
+
+func (s *FileAuditSink) Close() error {
+	return s.file.Close()
+}
+
+// VerifyAuditLog check the hash chain of an audit file written by FileAuditSink
+func VerifyAuditLog(r io.Reader) error {
+	_, err := verifyAuditChain(r)
+	return err
+}
+
+func verifyAuditChain(r io.Reader) (lastHash string, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return "", fmt.Errorf("line %d: %v", line, err)
+		}
+		if record.PrevHash != lastHash {
+			return "", fmt.Errorf("line %d: chain broken, previous hash mismatch", line)
+		}
+		hash, err := hashAuditRecord(lastHash, &record)
+		if err != nil {
+			return "", err
+		}
+		if hash != record.Hash {
+			return "", fmt.Errorf("line %d: record hash mismatch", line)
+		}
+		lastHash = hash
+	}
+	return lastHash, scanner.Err()
+}
+
+// hashAuditRecord sha256 of the previous hash and the record without its own hash
+func hashAuditRecord(prevHash string, r *AuditRecord) (string, error) {
+	unhashed := *r
+	unhashed.PrevHash = prevHash
+	unhashed.Hash = ""
+	b, err := json.Marshal(unhashed)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(prevHash), b...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ElasticAuditSink index records to Elasticsearch
+type ElasticAuditSink struct {
+	client *elastic.Client
+	index  string
+}
+
+func NewElasticAuditSink(client *elastic.Client, index string) *ElasticAuditSink {
+	return &ElasticAuditSink{
+		client: client,
+		index:  index,
+	}
+}
+
+func (s *ElasticAuditSink) Write(ctx context.Context, r *AuditRecord) error {
+	_, err := s.client.Index().
+		Index(s.index).
+		BodyJson(r).
+		Do(ctx)
+	return err
+}
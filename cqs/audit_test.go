@@ -0,0 +1,94 @@
+package cqs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jedrp/go-core/cqs"
+	"github.com/jedrp/go-core/infras"
+	"github.com/jedrp/go-core/pllog"
+	"google.golang.org/grpc/codes"
+)
+
+type changePasswordCommand struct {
+	UserName string
+	Password string
+	Question string `redact:"true"`
+}
+
+func (*changePasswordCommand) Execute(context.Context) *infras.Result {
+	return infras.Fail(codes.FailedPrecondition, "password reused")
+}
+
+func (*changePasswordCommand) SetDependences(context.Context, interface{}) {}
+
+func (*changePasswordCommand) IsCommand() []string { return []string{"audit"} }
+
+func TestAuditBehavior(t *testing.T) {
+	sink := cqs.NewMemoryAuditSink()
+	d := cqs.NewMemoryDispatcher(&pllog.DefaultLogger{}, 0).(*cqs.MemoryDispatcher)
+	d.Use("audit", cqs.AuditBehavior(sink, &pllog.DefaultLogger{}))
+	ctx := context.Background()
+	d.Register(ctx, &testDeps{}, &changePasswordCommand{})
+
+	ctx = infras.ContextWithPrincipal(ctx, "user-1")
+	ctx = context.WithValue(ctx, pllog.RequestID, "req-1")
+	d.Dispatch(ctx, &changePasswordCommand{UserName: "jed", Password: "secret", Question: "first pet"})
+
+	records := sink.Records()
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record but got %d", len(records))
+	}
+	r := records[0]
+	if r.Principal != "user-1" || r.RequestID != "req-1" || r.CommandType != "*cqs_test.changePasswordCommand" {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if r.Code != codes.FailedPrecondition.String() {
+		t.Errorf("expected FailedPrecondition but got %s", r.Code)
+	}
+	var payload map[string]string
+	json.Unmarshal(r.Payload, &payload)
+	if payload["UserName"] != "jed" || payload["Password"] != "[REDACTED]" || payload["Question"] != "[REDACTED]" {
+		t.Errorf("unexpected payload %s", r.Payload)
+	}
+}
+
+func TestFileAuditSinkChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	ctx := context.Background()
+
+	sink, err := cqs.NewFileAuditSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.Write(ctx, &cqs.AuditRecord{CommandType: "first", Payload: json.RawMessage(`{}`)})
+	sink.Close()
+
+	// reopen continue the chain
+	sink, err = cqs.NewFileAuditSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.Write(ctx, &cqs.AuditRecord{CommandType: "second", Payload: json.RawMessage(`{}`)})
+	sink.Close()
+
+	content, _ := ioutil.ReadFile(path)
+	if err := cqs.VerifyAuditLog(bytes.NewReader(content)); err != nil {
+		t.Errorf("expected valid chain but got %v", err)
+	}
+
+	tampered := bytes.Replace(content, []byte("first"), []byte("forged"), 1)
+	if err := cqs.VerifyAuditLog(bytes.NewReader(tampered)); err == nil {
+		t.Error("expected tampered log to fail verification")
+	}
+}
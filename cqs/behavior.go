@@ -0,0 +1,87 @@
+package cqs
+
+import (
+	"context"
+	"time"
+
+	"github.com/jedrp/go-core/infras"
+	"google.golang.org/grpc/codes"
+)
+
+// AllTags tag matching every executor, with or without tags
+const AllTags = "*"
+
+// HandlerFunc execute an executor, the end of the pipeline set dependences and call Execute
+type HandlerFunc func(ctx context.Context, e Executor) *infras.Result
+
+// Behavior wrap the execution of the executors it is attached to, call next to continue the pipeline
+type Behavior func(next HandlerFunc) HandlerFunc
+
+// AuthorizationPolicy return an error when the caller in ctx is not allowed to run the executor
+type AuthorizationPolicy func(ctx context.Context, e Executor) error
+
+type taggedBehavior struct {
+	tag      string
+	behavior Behavior
+}
+
+// TagsOf return the tags declared by IsCommand or IsQuery
+func TagsOf(e Executor) []string {
+	switch v := e.(type) {
+	case Command:
+		return v.IsCommand()
+	case Query:
+		return v.IsQuery()
+	}
+	return nil
+}
+
+// TimeoutBehavior cancel the executor context after d, use it to give "slow" executors their own budget
+func TimeoutBehavior(d time.Duration) Behavior {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, e Executor) *infras.Result {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next(ctx, e)
+		}
+	}
+}
+
+// AuthorizationBehavior reject the execution with PermissionDenied when the policy fail
+func AuthorizationBehavior(policy AuthorizationPolicy) Behavior {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, e Executor) *infras.Result {
+			if err := policy(ctx, e); err != nil {
+				return infras.Fail(codes.PermissionDenied, err.Error())
+			}
+			return next(ctx, e)
+		}
+	}
+}
+
+// buildPipeline wrap final with the behaviors attached to one of the tags, first attached is outermost
+func buildPipeline(behaviors []taggedBehavior, tags []string, final HandlerFunc) HandlerFunc {
+	var applicable []Behavior
+	for _, b := range behaviors {
+		if matchTag(b.tag, tags) {
+			applicable = append(applicable, b.behavior)
+		}
+	}
+	h := final
+	for i := len(applicable) - 1; i >= 0; i-- {
+		h = applicable[i](h)
+	}
+	return h
+}
+
+func matchTag(tag string, tags []string) bool {
+	if tag == AllTags {
+		return true
+	}
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package cqs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jedrp/go-core/cqs"
+	"github.com/jedrp/go-core/infras"
+	"github.com/jedrp/go-core/pllog"
+	"google.golang.org/grpc/codes"
+)
+
+func TestBehaviorsByTag(t *testing.T) {
+	d := cqs.NewMemoryDispatcher(&pllog.DefaultLogger{}, 0).(*cqs.MemoryDispatcher)
+	ctx := context.Background()
+
+	var calls []string
+	trace := func(name string) cqs.Behavior {
+		return func(next cqs.HandlerFunc) cqs.HandlerFunc {
+			return func(ctx context.Context, e cqs.Executor) *infras.Result {
+				calls = append(calls, name)
+				return next(ctx, e)
+			}
+		}
+	}
+	d.Use(cqs.AllTags, trace("all"))
+	d.Use("audit", trace("audit"))
+	d.Register(ctx, &testDeps{}, &taggedCommand{}, &taggedQuery{})
+	// attached after registration, must still be resolved
+	d.Use("slow", trace("slow"))
+
+	d.Dispatch(ctx, &taggedCommand{})
+	if len(calls) != 2 || calls[0] != "all" || calls[1] != "audit" {
+		t.Errorf("unexpected behaviors for command %v", calls)
+	}
+
+	calls = nil
+	d.Dispatch(ctx, &taggedQuery{})
+	if len(calls) != 2 || calls[0] != "all" || calls[1] != "slow" {
+		t.Errorf("unexpected behaviors for query %v", calls)
+	}
+}
+
+func TestAuthorizationBehavior(t *testing.T) {
+	d := cqs.NewMemoryDispatcher(&pllog.DefaultLogger{}, 0).(*cqs.MemoryDispatcher)
+	ctx := context.Background()
+	d.Use("audit", cqs.AuthorizationBehavior(func(ctx context.Context, e cqs.Executor) error {
+		return errors.New("admin only")
+	}))
+	d.Register(ctx, &testDeps{}, &taggedCommand{}, &testCommand{})
+
+	r := d.Dispatch(ctx, &taggedCommand{})
+	if r.Error == nil || r.Error.Code() != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied but got %v", r.Error)
+	}
+	r = d.Dispatch(ctx, &testCommand{})
+	if r.Error != nil {
+		t.Errorf("untagged command should not be authorized, got %v", r.Error)
+	}
+}
+
+func TestRateLimitBehavior(t *testing.T) {
+	d := cqs.NewMemoryDispatcher(&pllog.DefaultLogger{}, 0).(*cqs.MemoryDispatcher)
+	ctx := context.Background()
+	d.Use("slow", cqs.RateLimitBehavior(infras.NewRateLimiter(0.01, 1), true))
+	d.Register(ctx, &testDeps{}, &taggedQuery{})
+
+	aliceCtx := infras.ContextWithPrincipal(ctx, "alice")
+	if r := d.Dispatch(aliceCtx, &taggedQuery{}); r.Error.Code() == codes.ResourceExhausted {
+		t.Error("first call should not be limited")
+	}
+	r := d.Dispatch(aliceCtx, &taggedQuery{})
+	if r.Error == nil || r.Error.Code() != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted but got %v", r.Error)
+	}
+	if len(r.Error.Details()) != 1 {
+		t.Errorf("expected RetryInfo detail but got %v", r.Error.Details())
+	}
+	if r := d.Dispatch(infras.ContextWithPrincipal(ctx, "bob"), &taggedQuery{}); r.Error.Code() == codes.ResourceExhausted {
+		t.Error("other caller should have its own bucket")
+	}
+}
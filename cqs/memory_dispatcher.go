@@ -16,17 +16,25 @@ type MemoryDispatcher struct {
 	maxLatencyInMillisecond       time.Duration
 	logger                        pllog.PlLogger
 	registeredDependencesWrappers map[string]interface{}
+	registeredInfos               map[string]infras.ExecutorInfo
+	pipelines                     map[string]HandlerFunc
+	behaviors                     []taggedBehavior
+	stats                         *infras.ExecutorStatsCollector
 }
 
 var (
 	INVOKER_INTERNAL_ERROR = infras.Fail(codes.Internal, "An error occurt when server processing the request")
 )
 
+// NewMemoryDispatcher in-process dispatcher, the *MemoryDispatcher behind it is also an infras.Introspector
 func NewMemoryDispatcher(logger pllog.PlLogger, maxLatencyInMillisecond int64) Dispatcher {
 	return &MemoryDispatcher{
 		maxLatencyInMillisecond:       time.Duration(maxLatencyInMillisecond),
 		logger:                        logger,
 		registeredDependencesWrappers: make(map[string]interface{}),
+		registeredInfos:               make(map[string]infras.ExecutorInfo),
+		pipelines:                     make(map[string]HandlerFunc),
+		stats:                         infras.NewExecutorStatsCollector(),
 	}
 }
 
@@ -46,9 +54,29 @@ func (d *MemoryDispatcher) Register(ctx context.Context, deps interface{}, v ...
 		//test
 		e.SetDependences(ctx, deps)
 		d.registeredDependencesWrappers[typeName] = deps
+		d.registeredInfos[typeName] = newExecutorInfo(typeName, e)
+		d.pipelines[typeName] = d.resolvePipeline(typeName, deps)
 	}
 }
 
+// Use attach behaviors to the executors having the tag (AllTags for every executor).
+// Pipelines are resolved per executor type at registration, executors already registered are resolved again.
+func (d *MemoryDispatcher) Use(tag string, behaviors ...Behavior) {
+	for _, b := range behaviors {
+		d.behaviors = append(d.behaviors, taggedBehavior{tag, b})
+	}
+	for typeName, deps := range d.registeredDependencesWrappers {
+		d.pipelines[typeName] = d.resolvePipeline(typeName, deps)
+	}
+}
+
+func (d *MemoryDispatcher) resolvePipeline(typeName string, deps interface{}) HandlerFunc {
+	return buildPipeline(d.behaviors, d.registeredInfos[typeName].Tags, func(ctx context.Context, e Executor) *infras.Result {
+		e.SetDependences(ctx, deps)
+		return e.Execute(ctx)
+	})
+}
+
 func (d *MemoryDispatcher) Dispatch(ctx context.Context, e Executor) *infras.Result {
 	var (
 		cancel context.CancelFunc
@@ -59,11 +87,16 @@ func (d *MemoryDispatcher) Dispatch(ctx context.Context, e Executor) *infras.Res
 	}
 
 	typeName := reflect.TypeOf(e).String()
-	if depsWrapper, ok := d.registeredDependencesWrappers[typeName]; ok {
-		e.SetDependences(ctx, depsWrapper)
-		r := e.Execute(ctx)
+	if pipeline, ok := d.pipelines[typeName]; ok {
+		start := time.Now()
+		r := pipeline(ctx, e)
+		d.stats.Record(typeName, time.Since(start), r.Error != nil)
 		if r.Error != nil {
-			pllog.CreateLogEntryFromContext(ctx, d.logger).Error(r.Error.Err())
+			entry := pllog.CreateLogEntryFromContext(ctx, d.logger)
+			if r.Cause != nil {
+				entry = entry.WithError(r.Cause)
+			}
+			entry.Error(r.Error.Err())
 		}
 		return r
 	}
@@ -72,3 +105,28 @@ func (d *MemoryDispatcher) Dispatch(ctx context.Context, e Executor) *infras.Res
 	pllog.CreateLogEntryFromContext(ctx, d.logger).Error(msg)
 	return INVOKER_INTERNAL_ERROR
 }
+
+// Executors list registered commands and queries with their dispatch statistics
+func (d *MemoryDispatcher) Executors() []infras.ExecutorInfo {
+	infos := make([]infras.ExecutorInfo, 0, len(d.registeredInfos))
+	for typeName, info := range d.registeredInfos {
+		info.Stats = d.stats.Get(typeName)
+		infos = append(infos, info)
+	}
+	infras.SortExecutorInfos(infos)
+	return infos
+}
+
+func newExecutorInfo(typeName string, e Executor) infras.ExecutorInfo {
+	info := infras.ExecutorInfo{
+		TypeName: typeName,
+		Tags:     TagsOf(e),
+	}
+	switch e.(type) {
+	case Command:
+		info.Kind = infras.ExecutorKindCommand
+	case Query:
+		info.Kind = infras.ExecutorKindQuery
+	}
+	return info
+}
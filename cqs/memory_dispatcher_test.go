@@ -7,6 +7,7 @@ import (
 	"github.com/jedrp/go-core/cqs"
 	"github.com/jedrp/go-core/infras"
 	"github.com/jedrp/go-core/pllog"
+	"github.com/jedrp/go-core/result"
 	"google.golang.org/grpc/codes"
 )
 
@@ -56,3 +57,64 @@ func TestDitpatcher(t *testing.T) {
 		t.Errorf("expected Internal but got %v", r.Error.Code())
 	}
 }
+
+type taggedCommand struct{ testCommand }
+
+func (*taggedCommand) IsCommand() []string { return []string{"audit"} }
+
+type taggedQuery struct{ testQuery }
+
+func (*taggedQuery) IsQuery() []string { return []string{"slow"} }
+
+func TestDispatcherExecutors(t *testing.T) {
+	d := cqs.NewMemoryDispatcher(&pllog.DefaultLogger{}, 0).(*cqs.MemoryDispatcher)
+	ctx := context.Background()
+	d.Register(ctx, &testDeps{}, &taggedCommand{}, &taggedQuery{})
+
+	d.Dispatch(ctx, &taggedCommand{})
+	d.Dispatch(ctx, &taggedQuery{})
+	d.Dispatch(ctx, &taggedQuery{})
+
+	infos := d.Executors()
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 executors but got %d", len(infos))
+	}
+	cmd, query := infos[0], infos[1]
+	if cmd.TypeName != "*cqs_test.taggedCommand" || cmd.Kind != infras.ExecutorKindCommand || cmd.Tags[0] != "audit" {
+		t.Errorf("unexpected command info %+v", cmd)
+	}
+	if cmd.Stats.Dispatched != 1 || cmd.Stats.Failed != 0 {
+		t.Errorf("unexpected command stats %+v", cmd.Stats)
+	}
+	if query.Kind != infras.ExecutorKindQuery || query.Tags[0] != "slow" {
+		t.Errorf("unexpected query info %+v", query)
+	}
+	if query.Stats.Dispatched != 2 || query.Stats.Failed != 2 {
+		t.Errorf("unexpected query stats %+v", query.Stats)
+	}
+}
+
+type countUsersQuery struct{}
+
+func (q *countUsersQuery) ExecuteTyped(context.Context) *result.Result[int] {
+	return result.OK(3)
+}
+
+func (q *countUsersQuery) Execute(ctx context.Context) *infras.Result {
+	return q.ExecuteTyped(ctx).ToInfras()
+}
+
+func (*countUsersQuery) SetDependences(context.Context, interface{}) {}
+
+func (*countUsersQuery) IsQuery() []string { return nil }
+
+func TestDispatchQuery(t *testing.T) {
+	d := cqs.NewMemoryDispatcher(&pllog.DefaultLogger{}, 0)
+	ctx := context.Background()
+	d.Register(ctx, &testDeps{}, &countUsersQuery{})
+
+	count, err := cqs.DispatchQuery[int](ctx, d, &countUsersQuery{}).Unwrap()
+	if err != nil || count != 3 {
+		t.Errorf("expected 3 but got %v %v", count, err)
+	}
+}
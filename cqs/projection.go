@@ -0,0 +1,256 @@
+package cqs
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/jedrp/go-core/pllog"
+	"golang.org/x/sync/errgroup"
+)
+
+// Event a persisted event read from an event stream
+type Event struct {
+	Position  uint64
+	Type      string
+	Data      interface{}
+	Timestamp time.Time
+}
+
+// EventStream ordered, replayable source of events
+type EventStream interface {
+	// Read returns at most max events with position greater than from
+	Read(ctx context.Context, from uint64, max int) ([]*Event, error)
+	// Head returns the position of the last event of the stream
+	Head(ctx context.Context) (uint64, error)
+}
+
+// Projection build a read model from events, the read model is consumed by Queries
+type Projection interface {
+	Name() string
+	Apply(ctx context.Context, e *Event) error
+	// Reset drop the read model, called before the projection is rebuilt
+	Reset(ctx context.Context) error
+}
+
+// CheckpointStore keep the last processed position of every projection
+type CheckpointStore interface {
+	Load(ctx context.Context, projection string) (uint64, error)
+	Save(ctx context.Context, projection string, position uint64) error
+}
+
+// ProjectionMetrics receive projection progress, implement it to export to the metrics backend
+type ProjectionMetrics interface {
+	ObserveLag(projection string, lag uint64)
+	ObservePosition(projection string, position uint64)
+	IncFailure(projection string)
+}
+
+// MemoryCheckpointStore CheckpointStore backed by a map, checkpoints are lost on restart
+type MemoryCheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]uint64
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		checkpoints: make(map[string]uint64),
+	}
+}
+
+func (s *MemoryCheckpointStore) Load(ctx context.Context, projection string) (uint64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.checkpoints[projection], nil
+}
+
+func (s *MemoryCheckpointStore) Save(ctx context.Context, projection string, position uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[projection] = position
+	return nil
+}
+
+// ProjectionRunner poll the event stream and apply events to the registered projections
+type ProjectionRunner struct {
+	BatchSize    int
+	PollInterval time.Duration
+	RetryDelay   time.Duration
+	Metrics      ProjectionMetrics
+
+	logger      pllog.PlLogger
+	stream      EventStream
+	checkpoints CheckpointStore
+	mu          sync.Mutex
+	projections map[string]*projectionState
+}
+
+type projectionState struct {
+	projection Projection
+	// rebuilding is locked while the projection is applying a batch or being rebuilt
+	rebuilding sync.Mutex
+}
+
+func NewProjectionRunner(logger pllog.PlLogger, stream EventStream, checkpoints CheckpointStore) *ProjectionRunner {
+	return &ProjectionRunner{
+		BatchSize:    100,
+		PollInterval: time.Second,
+		RetryDelay:   5 * time.Second,
+		logger:       logger,
+		stream:       stream,
+		checkpoints:  checkpoints,
+		projections:  make(map[string]*projectionState),
+	}
+}
+
+// Register add projections to the runner, must be called before Run
+func (r *ProjectionRunner) Register(projections ...Projection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range projections {
+		name := p.Name()
+		r.logger.Infof("Registering projection %s", name)
+		if _, ok := r.projections[name]; ok {
+			r.logger.Panic(fmt.Sprintf("Duplicated projection registration detected of name: %s", name))
+		}
+		r.projections[name] = &projectionState{projection: p}
+	}
+}
+
+// Run process events for all projections until ctx is done
+func (r *ProjectionRunner) Run(ctx context.Context) error {
+	r.mu.Lock()
+	states := make([]*projectionState, 0, len(r.projections))
+	for _, s := range r.projections {
+		states = append(states, s)
+	}
+	r.mu.Unlock()
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, s := range states {
+		s := s
+		g.Go(func() error {
+			return r.run(ctx, s)
+		})
+	}
+	return g.Wait()
+}
+
+// Rebuild reset the projection read model and its checkpoint, the running loop then replays the stream from the beginning
+func (r *ProjectionRunner) Rebuild(ctx context.Context, name string) error {
+	r.mu.Lock()
+	s, ok := r.projections[name]
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("projection %s is not registered", name)
+	}
+
+	s.rebuilding.Lock()
+	defer s.rebuilding.Unlock()
+	r.logger.Infof("Rebuilding projection %s", name)
+	if err := s.projection.Reset(ctx); err != nil {
+		return err
+	}
+	return r.checkpoints.Save(ctx, name, 0)
+}
+
+// Lag return the number of events the projection still has to process
+func (r *ProjectionRunner) Lag(ctx context.Context, name string) (uint64, error) {
+	position, err := r.checkpoints.Load(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	head, err := r.stream.Head(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if head < position {
+		return 0, nil
+	}
+	return head - position, nil
+}
+
+func (r *ProjectionRunner) run(ctx context.Context, s *projectionState) error {
+	name := s.projection.Name()
+	for {
+		processed, err := r.catchUp(ctx, s)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			r.logger.Errorf("Projection %s fail to process events: %v", name, err)
+			if r.Metrics != nil {
+				r.Metrics.IncFailure(name)
+			}
+		}
+
+		var wait time.Duration
+		switch {
+		case err != nil:
+			wait = r.RetryDelay
+		case processed == 0:
+			wait = r.PollInterval
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// catchUp apply one batch of events and store the checkpoint, return the number of applied events
+func (r *ProjectionRunner) catchUp(ctx context.Context, s *projectionState) (processed int, err error) {
+	s.rebuilding.Lock()
+	defer s.rebuilding.Unlock()
+	defer func() {
+		if rErr := recover(); rErr != nil {
+			err = fmt.Errorf("projection panic: %v %s", rErr, string(debug.Stack()))
+		}
+	}()
+
+	name := s.projection.Name()
+	position, err := r.checkpoints.Load(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	events, err := r.stream.Read(ctx, position, r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range events {
+		if err := s.projection.Apply(ctx, e); err != nil {
+			return processed, fmt.Errorf("apply event %s at position %d: %v", e.Type, e.Position, err)
+		}
+		// checkpoint after each event so a failure does not replay events already applied
+		if err := r.checkpoints.Save(ctx, name, e.Position); err != nil {
+			return processed, err
+		}
+		position = e.Position
+		processed++
+	}
+
+	r.reportLag(ctx, name, position)
+	return processed, nil
+}
+
+func (r *ProjectionRunner) reportLag(ctx context.Context, name string, position uint64) {
+	head, err := r.stream.Head(ctx)
+	if err != nil {
+		r.logger.Warnf("Projection %s fail to read stream head: %v", name, err)
+		return
+	}
+	var lag uint64
+	if head > position {
+		lag = head - position
+	}
+	if lag > 0 {
+		r.logger.Debugf("Projection %s at position %d, lag %d", name, position, lag)
+	}
+	if r.Metrics != nil {
+		r.Metrics.ObservePosition(name, position)
+		r.Metrics.ObserveLag(name, lag)
+	}
+}
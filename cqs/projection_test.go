@@ -0,0 +1,107 @@
+package cqs_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jedrp/go-core/cqs"
+	"github.com/jedrp/go-core/pllog"
+	"github.com/sirupsen/logrus"
+)
+
+type testStream struct {
+	events []*cqs.Event
+}
+
+func (s *testStream) Read(ctx context.Context, from uint64, max int) ([]*cqs.Event, error) {
+	var res []*cqs.Event
+	for _, e := range s.events {
+		if e.Position > from && len(res) < max {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+func (s *testStream) Head(ctx context.Context) (uint64, error) {
+	if len(s.events) == 0 {
+		return 0, nil
+	}
+	return s.events[len(s.events)-1].Position, nil
+}
+
+type countProjection struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (p *countProjection) Name() string { return "count" }
+
+func (p *countProjection) Apply(ctx context.Context, e *cqs.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.count++
+	return nil
+}
+
+func (p *countProjection) Reset(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.count = 0
+	return nil
+}
+
+func (p *countProjection) Count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.count
+}
+
+func TestProjectionRunner(t *testing.T) {
+	stream := &testStream{}
+	for i := 1; i <= 5; i++ {
+		stream.events = append(stream.events, &cqs.Event{Position: uint64(i), Type: "created"})
+	}
+	checkpoints := cqs.NewMemoryCheckpointStore()
+	p := &countProjection{}
+
+	r := cqs.NewProjectionRunner(pllog.NewDefaultLogger(logrus.ErrorLevel), stream, checkpoints)
+	r.BatchSize = 2
+	r.PollInterval = 10 * time.Millisecond
+	r.Register(p)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- r.Run(ctx) }()
+
+	waitFor(t, func() bool { return p.Count() == 5 })
+	lag, err := r.Lag(ctx, "count")
+	if err != nil || lag != 0 {
+		t.Errorf("expected lag 0 but got %d (%v)", lag, err)
+	}
+
+	if err := r.Rebuild(ctx, "count"); err != nil {
+		t.Fatalf("rebuild fail: %v", err)
+	}
+	waitFor(t, func() bool { return p.Count() == 5 })
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("run should stop without error but got %v", err)
+	}
+	if pos, _ := checkpoints.Load(context.Background(), "count"); pos != 5 {
+		t.Errorf("expected checkpoint 5 but got %d", pos)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
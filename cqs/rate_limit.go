@@ -0,0 +1,27 @@
+package cqs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/jedrp/go-core/infras"
+	"google.golang.org/grpc/codes"
+)
+
+// RateLimitBehavior limit the executions per executor type, and per caller (principal in ctx) when byCaller is set.
+// Refused executions fail with ResourceExhausted carrying the retry delay.
+func RateLimitBehavior(limiter *infras.RateLimiter, byCaller bool) Behavior {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, e Executor) *infras.Result {
+			key := reflect.TypeOf(e).String()
+			if byCaller {
+				key = key + "|" + infras.PrincipalFromContext(ctx)
+			}
+			if ok, wait := limiter.Allow(key); !ok {
+				return infras.FailWithRetryDelay(codes.ResourceExhausted, fmt.Sprintf("rate limit exceeded for %s", reflect.TypeOf(e).String()), wait)
+			}
+			return next(ctx, e)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package cqs
+
+import (
+	"context"
+
+	"github.com/jedrp/go-core/result"
+)
+
+// TypedQuery Query declaring its result type. Execute must return ExecuteTyped(ctx).ToInfras(),
+// the compiler then check the handler returns T and DispatchQuery give callers a Result[T].
+type TypedQuery[T any] interface {
+	Query
+	ExecuteTyped(context.Context) *result.Result[T]
+}
+
+// DispatchQuery dispatch the query and type its result, no type assertion needed by the caller
+func DispatchQuery[T any](ctx context.Context, d Dispatcher, q TypedQuery[T]) *result.Result[T] {
+	return result.FromInfras[T](d.Dispatch(ctx, q))
+}
@@ -0,0 +1,36 @@
+package infras
+
+import "context"
+
+const (
+	// Principal context key of the authenticated caller identity
+	Principal = "Principal"
+	// Locale context key of the locales preferred by the caller
+	Locale = "Locale"
+)
+
+// ContextWithPrincipal set the authenticated caller identity, usually from the token subject
+func ContextWithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, Principal, principal)
+}
+
+// PrincipalFromContext return the caller identity or empty string for anonymous calls
+func PrincipalFromContext(ctx context.Context) string {
+	if p, ok := ctx.Value(Principal).(string); ok {
+		return p
+	}
+	return ""
+}
+
+// ContextWithLocale set the locales preferred by the caller, most preferred first
+func ContextWithLocale(ctx context.Context, locales ...string) context.Context {
+	return context.WithValue(ctx, Locale, locales)
+}
+
+// LocalesFromContext return the locales preferred by the caller, nil when unknown
+func LocalesFromContext(ctx context.Context) []string {
+	if l, ok := ctx.Value(Locale).([]string); ok {
+		return l
+	}
+	return nil
+}
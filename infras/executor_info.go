@@ -0,0 +1,108 @@
+package infras
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ExecutorKind kind of a registered executor
+type ExecutorKind string
+
+const (
+	ExecutorKindCommand ExecutorKind = "command"
+	ExecutorKindQuery   ExecutorKind = "query"
+)
+
+// ExecutorInfo describe an executor registered on a dispatcher
+type ExecutorInfo struct {
+	TypeName string        `json:"typeName"`
+	Kind     ExecutorKind  `json:"kind"`
+	Tags     []string      `json:"tags"`
+	Stats    ExecutorStats `json:"stats"`
+}
+
+// ExecutorStats dispatch statistics of an executor type
+type ExecutorStats struct {
+	Dispatched       uint64    `json:"dispatched"`
+	Failed           uint64    `json:"failed"`
+	AvgLatencyMs     float64   `json:"avgLatencyMs"`
+	MaxLatencyMs     float64   `json:"maxLatencyMs"`
+	LastDispatchedAt time.Time `json:"lastDispatchedAt"`
+}
+
+// Introspector implemented by dispatchers able to list their registered executors
+type Introspector interface {
+	Executors() []ExecutorInfo
+}
+
+// ExecutorStatsCollector concurrent safe dispatch statistics keyed by executor type name
+type ExecutorStatsCollector struct {
+	mu    sync.Mutex
+	stats map[string]*executorCounters
+}
+
+type executorCounters struct {
+	dispatched   uint64
+	failed       uint64
+	totalLatency time.Duration
+	maxLatency   time.Duration
+	last         time.Time
+}
+
+func NewExecutorStatsCollector() *ExecutorStatsCollector {
+	return &ExecutorStatsCollector{
+		stats: make(map[string]*executorCounters),
+	}
+}
+
+// Record add one dispatch of the type
+func (c *ExecutorStatsCollector) Record(typeName string, latency time.Duration, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.stats[typeName]
+	if !ok {
+		s = &executorCounters{}
+		c.stats[typeName] = s
+	}
+	s.dispatched++
+	if failed {
+		s.failed++
+	}
+	s.totalLatency += latency
+	if latency > s.maxLatency {
+		s.maxLatency = latency
+	}
+	s.last = time.Now()
+}
+
+// Get return a snapshot of the type statistics
+func (c *ExecutorStatsCollector) Get(typeName string) ExecutorStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.stats[typeName]
+	if !ok {
+		return ExecutorStats{}
+	}
+	stats := ExecutorStats{
+		Dispatched:       s.dispatched,
+		Failed:           s.failed,
+		MaxLatencyMs:     toMillisecond(s.maxLatency),
+		LastDispatchedAt: s.last,
+	}
+	if s.dispatched > 0 {
+		stats.AvgLatencyMs = toMillisecond(s.totalLatency) / float64(s.dispatched)
+	}
+	return stats
+}
+
+// SortExecutorInfos order infos by type name so listings are stable
+func SortExecutorInfos(infos []ExecutorInfo) {
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].TypeName < infos[j].TypeName
+	})
+}
+
+func toMillisecond(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,98 @@
+package infras
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusClientClosedRequest non standard status used by grpc-gateway for Canceled
+const statusClientClosedRequest = 499
+
+// HTTPStatusContextKey context key of the HTTPStatusOverrides of the service
+const HTTPStatusContextKey = "HTTPStatusOverrides"
+
+// HTTPStatusOverrides HTTP statuses of the codes answered differently by a service, e.g. {codes.Aborted: 409}
+type HTTPStatusOverrides map[codes.Code]int
+
+// ContextWithHTTPStatus set the overrides of the service answering the request, WriteResponse reads them
+// from the request carried by the writer (see WithRequest)
+func ContextWithHTTPStatus(ctx context.Context, overrides HTTPStatusOverrides) context.Context {
+	return context.WithValue(ctx, HTTPStatusContextKey, overrides)
+}
+
+// HTTPStatusFromContext HTTP status of the code, the override of the context when there is one
+func HTTPStatusFromContext(ctx context.Context, code codes.Code) int {
+	if overrides, ok := ctx.Value(HTTPStatusContextKey).(HTTPStatusOverrides); ok {
+		if s, ok := overrides[code]; ok {
+			return s
+		}
+	}
+	return HTTPStatusFromCode(code)
+}
+
+// HTTPStatusFromCode map gRPC code to HTTP status following grpc-gateway, except Aborted which
+// keep answering 412 for the existing clients (override it with HTTPStatusOverrides to change it)
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return statusClientClosedRequest
+	case codes.Unknown:
+		return http.StatusInternalServerError
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		// Note, this deliberately doesn't translate to the similarly named '412 Precondition Failed' HTTP response status.
+		return http.StatusBadRequest
+	case codes.Aborted:
+		return http.StatusPreconditionFailed
+	case codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Internal:
+		return http.StatusInternalServerError
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DataLoss:
+		return http.StatusInternalServerError
+	}
+	return http.StatusInternalServerError
+}
+
+// setRetryAfter write Retry-After from the RetryInfo detail of throttling and unavailability statuses
+func setRetryAfter(rw http.ResponseWriter, s *status.Status) {
+	if s.Code() != codes.ResourceExhausted && s.Code() != codes.Unavailable {
+		return
+	}
+	for _, d := range s.Details() {
+		if info, ok := d.(*errdetails.RetryInfo); ok {
+			delay, err := ptypes.Duration(info.RetryDelay)
+			if err != nil {
+				return
+			}
+			rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			return
+		}
+	}
+}
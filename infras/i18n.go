@@ -0,0 +1,171 @@
+package infras
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// ParamMetadataPrefix prefix of the ErrorInfo metadata keys of the message template params,
+// e.g. param.id for {id}, so the params can't collide with the other metadata
+const ParamMetadataPrefix = "param."
+
+// Messages default catalog used by WriteResponse and LocalizeStatus
+var Messages = NewMessageCatalog("en")
+
+// ParamsMetadata ErrorInfo metadata of the message template params
+func ParamsMetadata(params map[string]string) map[string]string {
+	metadata := make(map[string]string, len(params))
+	for k, v := range params {
+		metadata[ParamMetadataPrefix+k] = v
+	}
+	return metadata
+}
+
+// ParamsFromMetadata message template params of the ErrorInfo metadata, the other entries are ignored
+func ParamsFromMetadata(metadata map[string]string) map[string]string {
+	params := map[string]string{}
+	for k, v := range metadata {
+		if strings.HasPrefix(k, ParamMetadataPrefix) {
+			params[strings.TrimPrefix(k, ParamMetadataPrefix)] = v
+		}
+	}
+	return params
+}
+
+// MessageCatalog localized message templates keyed by locale and error code,
+// templates interpolate {name} parameters:
+//
+//	infras.Messages.Add("fr", map[string]string{"USER_NOT_FOUND": "utilisateur {id} introuvable"})
+type MessageCatalog struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]string
+}
+
+// NewMessageCatalog catalog falling back to the default locale when no requested locale has the message
+func NewMessageCatalog(defaultLocale string) *MessageCatalog {
+	return &MessageCatalog{
+		defaultLocale: normalizeLocale(defaultLocale),
+		messages:      map[string]map[string]string{},
+	}
+}
+
+// Add message templates of the locale keyed by error code
+func (c *MessageCatalog) Add(locale string, messages map[string]string) *MessageCatalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	locale = normalizeLocale(locale)
+	if c.messages[locale] == nil {
+		c.messages[locale] = map[string]string{}
+	}
+	for code, template := range messages {
+		c.messages[locale][code] = template
+	}
+	return c
+}
+
+// Lookup template of the error code in the first supported locale, a regional locale (fr-ca) falls back to its language (fr)
+func (c *MessageCatalog) Lookup(locales []string, errorCode string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	candidates := append(append(make([]string, 0, len(locales)+1), locales...), c.defaultLocale)
+	for _, locale := range candidates {
+		locale = normalizeLocale(locale)
+		for {
+			if template, ok := c.messages[locale][errorCode]; ok {
+				return template, true
+			}
+			i := strings.LastIndexByte(locale, '-')
+			if i < 0 {
+				break
+			}
+			locale = locale[:i]
+		}
+	}
+	return "", false
+}
+
+// Localize message of the error code in the locales of the context, the canonical message is returned when the catalog has none
+func (c *MessageCatalog) Localize(ctx context.Context, errorCode string, params map[string]string, canonical string) string {
+	template, ok := c.Lookup(LocalesFromContext(ctx), errorCode)
+	if !ok {
+		return canonical
+	}
+	return Interpolate(template, params)
+}
+
+// Interpolate replace the {name} placeholders by the params, unknown placeholders are kept
+func Interpolate(template string, params map[string]string) string {
+	if len(params) == 0 {
+		return template
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// LocalizeStatus status with the message of the ErrorInfo reason (canonical code name when missing) localized
+// in the locales of the context, the template params are the param. metadata (see ParamsMetadata) and the details are kept
+func LocalizeStatus(ctx context.Context, s *status.Status) *status.Status {
+	errorCode := CodeName(s.Code())
+	var params map[string]string
+	for _, d := range s.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			errorCode = info.Type
+			params = ParamsFromMetadata(info.Metadata)
+		}
+	}
+	message := Messages.Localize(ctx, errorCode, params, s.Message())
+	if message == s.Message() {
+		return s
+	}
+	p := s.Proto()
+	p.Message = message
+	return status.FromProto(p)
+}
+
+// ParseAcceptLanguage locales of an Accept-Language header ordered by quality
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var ranges []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := strings.TrimSpace(fields[0])
+		if locale == "" || locale == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, weighted{normalizeLocale(locale), q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	locales := make([]string, len(ranges))
+	for i, r := range ranges {
+		locales[i] = r.locale
+	}
+	return locales
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(locale, "_", "-", -1))
+}
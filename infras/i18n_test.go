@@ -0,0 +1,75 @@
+package infras_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/jedrp/go-core/infras"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	locales := infras.ParseAcceptLanguage("fr-CA;q=0.8, en;q=0.5, vi_VN, *;q=0.1, de;q=0")
+	if expected := []string{"vi-vn", "fr-ca", "en"}; !reflect.DeepEqual(locales, expected) {
+		t.Errorf("expected %v but got %v", expected, locales)
+	}
+}
+
+func TestMessageCatalog(t *testing.T) {
+	catalog := infras.NewMessageCatalog("en").
+		Add("en", map[string]string{"USER_NOT_FOUND": "user {id} not found"}).
+		Add("fr", map[string]string{"USER_NOT_FOUND": "utilisateur {id} introuvable"})
+	params := map[string]string{"id": "42"}
+
+	tt := []struct {
+		locales  []string
+		expected string
+	}{
+		{[]string{"fr-CA"}, "utilisateur 42 introuvable"},
+		{[]string{"de", "fr"}, "utilisateur 42 introuvable"},
+		{[]string{"de"}, "user 42 not found"},
+		{nil, "user 42 not found"},
+	}
+	for _, tc := range tt {
+		ctx := infras.ContextWithLocale(context.Background(), tc.locales...)
+		if msg := catalog.Localize(ctx, "USER_NOT_FOUND", params, "canonical"); msg != tc.expected {
+			t.Errorf("expected %q for %v but got %q", tc.expected, tc.locales, msg)
+		}
+	}
+	if msg := catalog.Localize(context.Background(), "ORDER_NOT_FOUND", params, "canonical"); msg != "canonical" {
+		t.Errorf("expected the canonical message but got %q", msg)
+	}
+}
+
+func TestWriteResponseLocalized(t *testing.T) {
+	infras.Messages.Add("fr", map[string]string{"I18N_TEST_NOT_FOUND": "produit {sku} introuvable"})
+	result := infras.Fail(codes.NotFound, "product not found").
+		WithErrorInfo("I18N_TEST_NOT_FOUND", "test", infras.ParamsMetadata(map[string]string{"sku": "A1"}))
+
+	req := httptest.NewRequest("GET", "/products/A1", nil)
+	req = req.WithContext(infras.ContextWithLocale(req.Context(), "fr"))
+	rec := httptest.NewRecorder()
+	result.WriteResponse(infras.WithRequest(rec, req), runtime.JSONProducer())
+
+	var body map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if body["Message"] != "produit A1 introuvable" || body["ErrorCode"] != "I18N_TEST_NOT_FOUND" {
+		t.Errorf("unexpected body %s", rec.Body.String())
+	}
+	if result.Error.Message() != "product not found" {
+		t.Errorf("the result should keep the canonical message but got %s", result.Error.Message())
+	}
+
+	s := infras.LocalizeStatus(infras.ContextWithLocale(context.Background(), "fr"), result.Error)
+	if s.Message() != "produit A1 introuvable" || len(s.Details()) != 1 {
+		t.Errorf("expected localized status with details but got %v", s.Proto())
+	}
+	if s := infras.LocalizeStatus(context.Background(), status.New(codes.Internal, "boom")); s.Message() != "boom" {
+		t.Errorf("expected status without catalog entry as is but got %s", s.Message())
+	}
+}
@@ -0,0 +1,206 @@
+package infras
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	// DefaultPageSize page size of requests without one
+	DefaultPageSize = 20
+	// MaxPageSize larger page sizes are reduced to it
+	MaxPageSize = 100
+	// PageTokens codec of the page tokens, without key until configured. The key must be shared by the
+	// instances serving the same API so the tokens survive restarts and load balancing, CoreServerV2 sets it
+	// from PAGE_TOKEN_KEY:
+	//
+	//	infras.PageTokens = infras.NewPageTokenCodec([]byte(os.Getenv("PAGE_TOKEN_KEY")))
+	PageTokens = NewPageTokenCodec(nil)
+	// ErrInvalidPageToken page token altered or issued with another key
+	ErrInvalidPageToken = errors.New("invalid page token")
+	// ErrPageTokenKeyMissing paging with the codec without key, see PageTokens
+	ErrPageTokenKeyMissing = errors.New("page token key not configured, set PAGE_TOKEN_KEY or infras.PageTokens")
+)
+
+// PageRequest paging parameters of a list query, page_token and page_size as in the Google API design guide
+type PageRequest struct {
+	PageToken string `json:"page_token,omitempty"`
+	PageSize  int    `json:"page_size,omitempty"`
+}
+
+// PageRequestFromQuery paging parameters of the page_token and page_size query params
+func PageRequestFromQuery(query url.Values) PageRequest {
+	size, _ := strconv.Atoi(query.Get("page_size"))
+	return PageRequest{PageToken: query.Get("page_token"), PageSize: size}
+}
+
+// Size page size within the limits, DefaultPageSize when missing and at most MaxPageSize
+func (r PageRequest) Size() int {
+	if r.PageSize <= 0 {
+		return DefaultPageSize
+	}
+	if r.PageSize > MaxPageSize {
+		return MaxPageSize
+	}
+	return r.PageSize
+}
+
+// Position decode the position of the page token into v, false for the first page
+func (r PageRequest) Position(v interface{}) (bool, error) {
+	if r.PageToken == "" {
+		return false, nil
+	}
+	return true, PageTokens.Decode(r.PageToken, v)
+}
+
+// Paged implemented by pages so WriteResponse can link the next page and cqrs.ToValue render them
+// with the REST field names
+type Paged interface {
+	PageItems() interface{}
+	NextPage() (token string)
+	PageTotalCount() (total int64, ok bool)
+}
+
+// Page items of a list query with the token of the next page, empty on the last page.
+// TotalCount is optional, counting is often more expensive than the query
+type Page[T any] struct {
+	Items         []T    `json:"items"`
+	NextPageToken string `json:"next_page_token,omitempty"`
+	TotalCount    *int64 `json:"total_count,omitempty"`
+}
+
+// NewPage page of the items, nextPosition is encoded in the next page token, nil on the last page:
+//
+//	page, err := infras.NewPage(users, lastID)
+func NewPage[T any](items []T, nextPosition interface{}) (*Page[T], error) {
+	p := &Page[T]{Items: items}
+	if nextPosition != nil {
+		token, err := PageTokens.Encode(nextPosition)
+		if err != nil {
+			return nil, err
+		}
+		p.NextPageToken = token
+	}
+	return p, nil
+}
+
+// WithTotalCount set the total count of items of the query
+func (p *Page[T]) WithTotalCount(total int64) *Page[T] {
+	p.TotalCount = &total
+	return p
+}
+
+func (p *Page[T]) PageItems() interface{} {
+	return p.Items
+}
+
+func (p *Page[T]) NextPage() string {
+	return p.NextPageToken
+}
+
+func (p *Page[T]) PageTotalCount() (int64, bool) {
+	if p.TotalCount == nil {
+		return 0, false
+	}
+	return *p.TotalCount, true
+}
+
+// PageTokenCodec opaque page tokens, the JSON position signed with HMAC-SHA256 so clients can't forge them
+type PageTokenCodec struct {
+	key      []byte
+	unsigned bool
+}
+
+// NewPageTokenCodec codec signing with the key, encoding and decoding fail with ErrPageTokenKeyMissing when empty
+func NewPageTokenCodec(key []byte) *PageTokenCodec {
+	return &PageTokenCodec{key: key}
+}
+
+// NewUnsignedPageTokenCodec codec of tokens without signature, for positions clients are free to forge
+func NewUnsignedPageTokenCodec() *PageTokenCodec {
+	return &PageTokenCodec{unsigned: true}
+}
+
+// Encode token of the position
+func (c *PageTokenCodec) Encode(position interface{}) (string, error) {
+	if len(c.key) == 0 && !c.unsigned {
+		return "", ErrPageTokenKeyMissing
+	}
+	payload, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	if c.unsigned {
+		return base64.RawURLEncoding.EncodeToString(payload), nil
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload)), nil
+}
+
+// Decode position of the token into v, ErrInvalidPageToken when the token is malformed or its signature does not match
+func (c *PageTokenCodec) Decode(token string, v interface{}) error {
+	if len(c.key) == 0 && !c.unsigned {
+		return ErrPageTokenKeyMissing
+	}
+	encoded := token
+	i := strings.IndexByte(token, '.')
+	if !c.unsigned {
+		if i < 0 {
+			return ErrInvalidPageToken
+		}
+		encoded = token[:i]
+	} else if i >= 0 {
+		return ErrInvalidPageToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidPageToken
+	}
+	if !c.unsigned {
+		signature, err := base64.RawURLEncoding.DecodeString(token[i+1:])
+		if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+			return ErrInvalidPageToken
+		}
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return ErrInvalidPageToken
+	}
+	return nil
+}
+
+func (c *PageTokenCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// setPageLinks RFC 8288 Link header of the first and next pages relative to the request URL, X-Total-Count when known
+func setPageLinks(rw http.ResponseWriter, p Paged) {
+	if total, ok := p.PageTotalCount(); ok {
+		rw.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	}
+	r := RequestFromResponseWriter(rw)
+	if r == nil {
+		return
+	}
+	link := func(token, rel string) string {
+		u := *r.URL
+		q := u.Query()
+		q.Del("page_token")
+		if token != "" {
+			q.Set("page_token", token)
+		}
+		u.RawQuery = q.Encode()
+		return "<" + u.String() + ">; rel=\"" + rel + "\""
+	}
+	rw.Header().Add("Link", link("", "first"))
+	if next := p.NextPage(); next != "" {
+		rw.Header().Add("Link", link(next, "next"))
+	}
+}
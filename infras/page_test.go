@@ -0,0 +1,117 @@
+package infras_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/jedrp/go-core/infras"
+)
+
+func TestPageToken(t *testing.T) {
+	type position struct {
+		LastID int `json:"last_id"`
+	}
+	codec := infras.NewPageTokenCodec([]byte("secret"))
+	token, err := codec.Encode(position{LastID: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p position
+	if err := codec.Decode(token, &p); err != nil || p.LastID != 42 {
+		t.Errorf("expected position back but got %v %v", p, err)
+	}
+
+	forged, _ := infras.NewPageTokenCodec([]byte("other")).Encode(position{LastID: 1})
+	for _, invalid := range []string{forged, token[1:], "not-a-token", token + "x"} {
+		if err := codec.Decode(invalid, &p); err != infras.ErrInvalidPageToken {
+			t.Errorf("expected %q to be rejected but got %v", invalid, err)
+		}
+	}
+}
+
+func TestPageTokenModes(t *testing.T) {
+	if _, err := infras.NewPageTokenCodec(nil).Encode(1); err != infras.ErrPageTokenKeyMissing {
+		t.Errorf("expected the codec without key to fail but got %v", err)
+	}
+	codec := infras.NewUnsignedPageTokenCodec()
+	token, err := codec.Encode(map[string]int{"offset": 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p map[string]int
+	if err := codec.Decode(token, &p); err != nil || p["offset"] != 40 {
+		t.Errorf("expected unsigned position back but got %v %v", p, err)
+	}
+	signed, _ := infras.NewPageTokenCodec([]byte("secret")).Encode(1)
+	if err := codec.Decode(signed, &p); err != infras.ErrInvalidPageToken {
+		t.Errorf("expected signed token to be rejected by the unsigned codec but got %v", err)
+	}
+}
+
+func useTestPageTokens(t *testing.T) {
+	tokens := infras.PageTokens
+	infras.PageTokens = infras.NewPageTokenCodec([]byte("secret"))
+	t.Cleanup(func() { infras.PageTokens = tokens })
+}
+
+func TestPageRequest(t *testing.T) {
+	useTestPageTokens(t)
+	tt := []struct {
+		query    string
+		expected int
+	}{
+		{"", infras.DefaultPageSize},
+		{"page_size=-1", infras.DefaultPageSize},
+		{"page_size=10", 10},
+		{"page_size=1000", infras.MaxPageSize},
+	}
+	for _, tc := range tt {
+		q, _ := url.ParseQuery(tc.query)
+		if size := infras.PageRequestFromQuery(q).Size(); size != tc.expected {
+			t.Errorf("expected %d for %q but got %d", tc.expected, tc.query, size)
+		}
+	}
+
+	page, _ := infras.NewPage([]string{"a"}, 7)
+	var last int
+	if first, err := (infras.PageRequest{PageToken: page.NextPageToken}).Position(&last); !first || err != nil || last != 7 {
+		t.Errorf("expected position 7 but got %v %v %v", first, last, err)
+	}
+}
+
+func TestWriteResponsePage(t *testing.T) {
+	useTestPageTokens(t)
+	page, err := infras.NewPage([]string{"a", "b"}, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page.WithTotalCount(5)
+
+	req := httptest.NewRequest("GET", "/users?page_size=2&page_token=old&sort=name", nil)
+	rec := httptest.NewRecorder()
+	infras.OK(page).WriteResponse(infras.WithRequest(rec, req), runtime.JSONProducer())
+
+	links := rec.Header()["Link"]
+	next := "</users?page_size=2&page_token=" + url.QueryEscape(page.NextPageToken) + "&sort=name>; rel=\"next\""
+	if len(links) != 2 || links[0] != "</users?page_size=2&sort=name>; rel=\"first\"" || links[1] != next {
+		t.Errorf("unexpected links %v", links)
+	}
+	if rec.Header().Get("X-Total-Count") != "5" {
+		t.Errorf("expected total count header but got %v", rec.Header())
+	}
+	var body map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if body["next_page_token"] != page.NextPageToken || body["total_count"] != float64(5) {
+		t.Errorf("unexpected body %s", rec.Body.String())
+	}
+
+	last, _ := infras.NewPage([]string{"c"}, nil)
+	rec = httptest.NewRecorder()
+	infras.OK(last).WriteResponse(infras.WithRequest(rec, req), runtime.JSONProducer())
+	if links := rec.Header()["Link"]; len(links) != 1 {
+		t.Errorf("last page should only link the first page but got %v", links)
+	}
+}
@@ -0,0 +1,131 @@
+package infras
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/jedrp/go-core/pllog"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProblemContentType media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeURIPrefix prefix of the problem type URI, the error code in kebab case is appended
+var ProblemTypeURIPrefix = "urn:problem-type:"
+
+// ProblemDetails RFC 7807 error body, Extensions are written as top level members
+type ProblemDetails struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+// NewProblemDetails problem with type derived from the error code, and errorCode extension member
+func NewProblemDetails(httpStatus int, errorCode string, detail string) *ProblemDetails {
+	return &ProblemDetails{
+		Type:   ProblemTypeURIPrefix + kebabCase(errorCode),
+		Title:  http.StatusText(httpStatus),
+		Status: httpStatus,
+		Detail: detail,
+		Extensions: map[string]interface{}{
+			"errorCode": errorCode,
+		},
+	}
+}
+
+func (p *ProblemDetails) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// WriteProblem write the problem, instance is set to the request ID when the writer carry the request
+func WriteProblem(rw http.ResponseWriter, p *ProblemDetails) {
+	if r := RequestFromResponseWriter(rw); r != nil && p.Instance == "" {
+		if reqID, ok := r.Context().Value(pllog.RequestID).(string); ok {
+			p.Instance = reqID
+		}
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+	rw.Header().Set("Content-Type", ProblemContentType)
+	rw.WriteHeader(p.Status)
+	rw.Write(body)
+}
+
+// AcceptsProblem true when the request carried by the writer accept application/problem+json
+func AcceptsProblem(rw http.ResponseWriter) bool {
+	r := RequestFromResponseWriter(rw)
+	if r == nil {
+		return false
+	}
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil || params["q"] == "0" {
+				continue
+			}
+			if mediaType == ProblemContentType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// problemFromStatus problem of a gRPC status, the ErrorInfo reason is the error code and BadRequest field violations are added as the fieldViolations extension
+func problemFromStatus(httpStatus int, s *status.Status) *ProblemDetails {
+	errorCode := CodeName(s.Code())
+	for _, d := range s.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			errorCode = info.Type
+		}
+	}
+	p := NewProblemDetails(httpStatus, errorCode, s.Message())
+	if violations := FieldViolationsOf(s); len(violations) > 0 {
+		p.Extensions["fieldViolations"] = violations
+	}
+	if details := renderDetails(s); len(details) > 0 {
+		p.Extensions["details"] = details
+	}
+	return p
+}
+
+// CodeName canonical upper snake case name of the code, as in google.rpc.Code (NOT_FOUND, ...)
+func CodeName(code codes.Code) string {
+	name := code.String()
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+func kebabCase(errorCode string) string {
+	return strings.ToLower(strings.Replace(errorCode, "_", "-", -1))
+}
@@ -0,0 +1,74 @@
+package infras
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const bucketSweepInterval = time.Minute
+
+// RateLimiter token bucket rate limiter, every key has its own bucket
+type RateLimiter struct {
+	ratePerSecond float64
+	burst         float64
+	mu            sync.Mutex
+	buckets       map[string]*tokenBucket
+	lastSweep     time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter allow ratePerSecond requests per key with bursts up to burst requests
+func NewRateLimiter(ratePerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		ratePerSecond: ratePerSecond,
+		burst:         float64(burst),
+		buckets:       make(map[string]*tokenBucket),
+		lastSweep:     time.Now(),
+	}
+}
+
+// Allow take a token from the key bucket, when refused return the wait before a token is available
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.ratePerSecond)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if l.ratePerSecond <= 0 {
+		return false, time.Duration(math.MaxInt64)
+	}
+	wait := time.Duration((1 - b.tokens) / l.ratePerSecond * float64(time.Second))
+	return false, wait
+}
+
+// sweep drop the buckets refilled to burst, they behave the same as a new bucket
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < bucketSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.ratePerSecond >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
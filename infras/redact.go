@@ -0,0 +1,87 @@
+package infras
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/jedrp/go-core/redact"
+	"google.golang.org/grpc/status"
+)
+
+// RedactStatus status with the sensitive data masked by redact.Default before it is sent to clients: the message
+// and every string of the details (ErrorInfo metadata, field violation descriptions, localized messages...)
+func RedactStatus(s *status.Status) *status.Status {
+	p := s.Proto()
+	changed := false
+	if message := redact.String(p.Message); message != p.Message {
+		p.Message = message
+		changed = true
+	}
+	for i, d := range p.Details {
+		var detail ptypes.DynamicAny
+		if err := ptypes.UnmarshalAny(d, &detail); err != nil {
+			continue
+		}
+		if !redactStrings(reflect.ValueOf(detail.Message)) {
+			continue
+		}
+		a, err := ptypes.MarshalAny(detail.Message)
+		if err != nil {
+			continue
+		}
+		p.Details[i] = a
+		changed = true
+	}
+	if !changed {
+		return s
+	}
+	return status.FromProto(p)
+}
+
+// redactStrings redact the strings of the detail in place, string maps entries of the redacted field names are masked,
+// returns true when something changed
+func redactStrings(v reflect.Value) bool {
+	changed := false
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			changed = redactStrings(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Type().Field(i); f.PkgPath == "" && !strings.HasPrefix(f.Name, "XXX_") {
+				changed = redactStrings(v.Field(i)) || changed
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			changed = redactStrings(v.Index(i)) || changed
+		}
+	case reflect.String:
+		if s := redact.String(v.String()); s != v.String() && v.CanSet() {
+			v.SetString(s)
+			changed = true
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.String {
+			return false
+		}
+		for _, k := range v.MapKeys() {
+			value := v.MapIndex(k).String()
+			redacted := redact.String(value)
+			name := k.String()
+			if i := strings.LastIndexByte(name, '.'); i >= 0 {
+				name = name[i+1:] // param.token is the token param
+			}
+			if redact.Default.IsField(name) {
+				redacted = redact.Mask
+			}
+			if redacted != value {
+				v.SetMapIndex(k, reflect.ValueOf(redacted).Convert(v.Type().Elem()))
+				changed = true
+			}
+		}
+	}
+	return changed
+}
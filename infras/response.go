@@ -0,0 +1,252 @@
+package infras
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+)
+
+const defaultFlushEvery = 100
+
+// ResponseMeta HTTP metadata of a response, the zero value keep the defaults of WriteResponse
+type ResponseMeta struct {
+	StatusCode int // overrides the status of successful responses when not zero
+	Header     http.Header
+	Cookies    []*http.Cookie
+}
+
+// WriteHeader write the headers and cookies then the status code, defaultStatus when not overridden
+func (m *ResponseMeta) WriteHeader(rw http.ResponseWriter, defaultStatus int) {
+	m.SetHeaders(rw)
+	if m != nil && m.StatusCode != 0 {
+		defaultStatus = m.StatusCode
+	}
+	rw.WriteHeader(defaultStatus)
+}
+
+// SetHeaders set the headers and cookies without the status code, failures keep them as well
+func (m *ResponseMeta) SetHeaders(rw http.ResponseWriter) {
+	if m == nil {
+		return
+	}
+	for k, values := range m.Header {
+		for _, v := range values {
+			rw.Header().Add(k, v)
+		}
+	}
+	for _, c := range m.Cookies {
+		http.SetCookie(rw, c)
+	}
+}
+
+// HasBody false for statuses without content (204, 304)
+func (m *ResponseMeta) HasBody() bool {
+	return m == nil || (m.StatusCode != http.StatusNoContent && m.StatusCode != http.StatusNotModified)
+}
+
+// Clone deep copy of the metadata, an empty one for nil
+func (m *ResponseMeta) Clone() *ResponseMeta {
+	c := &ResponseMeta{Header: http.Header{}}
+	if m != nil {
+		c.StatusCode = m.StatusCode
+		for k, v := range m.Header {
+			c.Header[k] = append([]string(nil), v...)
+		}
+		c.Cookies = append(c.Cookies, m.Cookies...)
+	}
+	return c
+}
+
+// WriteValue write a successful response, streamed for Streamer and Streams values, produced otherwise,
+// pages get the Link header of the next page
+func WriteValue(rw http.ResponseWriter, producer runtime.Producer, meta *ResponseMeta, v interface{}) {
+	switch v := v.(type) {
+	case Streamer:
+		WriteStream(rw, meta, v)
+		return
+	case Streams:
+		if s := NegotiateStream(rw, v); s != nil {
+			WriteStream(rw, meta, s)
+			return
+		}
+	}
+	if p, ok := v.(Paged); ok {
+		setPageLinks(rw, p)
+	}
+	meta.WriteHeader(rw, http.StatusOK)
+	if !meta.HasBody() {
+		return
+	}
+	if err := producer.Produce(rw, v); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+// Created 201 Created result with the Location of the new resource
+func Created(v interface{}, location string) *Result {
+	return OK(v).WithStatusCode(http.StatusCreated).WithHeader("Location", location)
+}
+
+// NoContent 204 No Content result
+func NoContent() *Result {
+	return OK(nil).WithStatusCode(http.StatusNoContent)
+}
+
+// WithStatusCode copy of the result answering the HTTP status when successful
+func (r *Result) WithStatusCode(statusCode int) *Result {
+	c := *r
+	c.Meta = r.Meta.Clone()
+	c.Meta.StatusCode = statusCode
+	return &c
+}
+
+// WithHeader copy of the result with the response header added
+func (r *Result) WithHeader(key, value string) *Result {
+	c := *r
+	c.Meta = r.Meta.Clone()
+	c.Meta.Header.Add(key, value)
+	return &c
+}
+
+// WithCookie copy of the result setting the cookie
+func (r *Result) WithCookie(cookie *http.Cookie) *Result {
+	c := *r
+	c.Meta = r.Meta.Clone()
+	c.Meta.Cookies = append(c.Meta.Cookies, cookie)
+	return &c
+}
+
+// Streamer value written incrementally by WriteResponse instead of the producer, for large query results
+type Streamer interface {
+	ContentType() string
+	// Stream write the value to w, flush send the buffered content to the client
+	Stream(w io.Writer, flush func()) error
+}
+
+// Streams alternative representations of a streamed value, WriteResponse choose by the Accept header, first one by default:
+//
+//	infras.OK(infras.Streams{&infras.NDJSONStream{Each: each}, &infras.CSVStream{Header: header, Each: eachRow}})
+type Streams []Streamer
+
+// NDJSONStream newline delimited JSON stream, Each emit the items one by one
+type NDJSONStream struct {
+	Each       func(emit func(item interface{}) error) error
+	FlushEvery int // items written between flushes, 100 by default
+}
+
+func (s *NDJSONStream) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (s *NDJSONStream) Stream(w io.Writer, flush func()) error {
+	enc := json.NewEncoder(w)
+	return streamItems(flush, s.FlushEvery, func(emit func() error) error {
+		return s.Each(func(item interface{}) error {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+			return emit()
+		})
+	})
+}
+
+// CSVStream CSV stream with an optional header row, Each emit the records one by one
+type CSVStream struct {
+	Header     []string
+	Each       func(emit func(record []string) error) error
+	FlushEvery int // records written between flushes, 100 by default
+}
+
+func (s *CSVStream) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (s *CSVStream) Stream(w io.Writer, flush func()) error {
+	cw := csv.NewWriter(w)
+	if len(s.Header) > 0 {
+		if err := cw.Write(s.Header); err != nil {
+			return err
+		}
+	}
+	err := streamItems(func() {
+		cw.Flush()
+		flush()
+	}, s.FlushEvery, func(emit func() error) error {
+		return s.Each(func(record []string) error {
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+			return emit()
+		})
+	})
+	cw.Flush()
+	if err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// streamItems call flush every flushEvery emitted items and at the end
+func streamItems(flush func(), flushEvery int, each func(emit func() error) error) error {
+	if flushEvery < 1 {
+		flushEvery = defaultFlushEvery
+	}
+	n := 0
+	err := each(func() error {
+		n++
+		if n%flushEvery == 0 {
+			flush()
+		}
+		return nil
+	})
+	flush()
+	return err
+}
+
+// WriteStream write the stream with the metadata, once the headers are sent a failing stream
+// aborts the response with http.ErrAbortHandler so the client does not take it as complete
+func WriteStream(rw http.ResponseWriter, meta *ResponseMeta, s Streamer) {
+	rw.Header().Set("Content-Type", s.ContentType())
+	meta.WriteHeader(rw, http.StatusOK)
+	bw := bufio.NewWriter(rw)
+	flusher, _ := rw.(http.Flusher)
+	flush := func() {
+		if bw.Flush() == nil && flusher != nil {
+			flusher.Flush()
+		}
+	}
+	err := s.Stream(bw, flush)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// NegotiateStream streamer of the best media type accepted by the request of the writer, see WithRequest
+func NegotiateStream(rw http.ResponseWriter, streams Streams) Streamer {
+	if len(streams) == 0 {
+		return nil
+	}
+	if r := RequestFromResponseWriter(rw); r != nil {
+		for _, mediaRange := range strings.Split(r.Header.Get("Accept"), ",") {
+			accepted, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil {
+				continue
+			}
+			for _, s := range streams {
+				if mediaType, _, _ := mime.ParseMediaType(s.ContentType()); mediaType == accepted {
+					return s
+				}
+			}
+		}
+	}
+	return streams[0]
+}
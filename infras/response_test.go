@@ -0,0 +1,93 @@
+package infras_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/jedrp/go-core/infras"
+	"google.golang.org/grpc/codes"
+)
+
+func TestWriteResponseMeta(t *testing.T) {
+	rec := httptest.NewRecorder()
+	infras.Created(map[string]int{"id": 1}, "/users/1").
+		WithCookie(&http.Cookie{Name: "session", Value: "abc"}).
+		WriteResponse(rec, runtime.JSONProducer())
+	if rec.Code != 201 || rec.Header().Get("Location") != "/users/1" || rec.Header().Get("Set-Cookie") != "session=abc" {
+		t.Errorf("unexpected response %d %v", rec.Code, rec.Header())
+	}
+	if rec.Body.String() != "{\"id\":1}\n" {
+		t.Errorf("unexpected body %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	infras.NoContent().WriteResponse(rec, runtime.JSONProducer())
+	if rec.Code != 204 || rec.Body.Len() != 0 {
+		t.Errorf("expected empty 204 but got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	infras.Fail(codes.NotFound, "missing").WithStatusCode(201).WithHeader("X-Trace", "1").WriteResponse(rec, runtime.JSONProducer())
+	if rec.Code != 404 || rec.Header().Get("X-Trace") != "1" {
+		t.Errorf("failures should keep their status and the headers but got %d %v", rec.Code, rec.Header())
+	}
+
+	base := infras.OK(1)
+	base.WithHeader("X-Trace", "1")
+	if base.Meta != nil {
+		t.Error("builders should not modify the original result")
+	}
+}
+
+func TestWriteResponseStream(t *testing.T) {
+	each := func(emit func(item interface{}) error) error {
+		for i := 1; i <= 3; i++ {
+			if err := emit(map[string]int{"id": i}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	eachRow := func(emit func(record []string) error) error {
+		for i := 1; i <= 3; i++ {
+			if err := emit([]string{strconv.Itoa(i), "user, " + strconv.Itoa(i)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	result := infras.OK(infras.Streams{
+		&infras.NDJSONStream{Each: each, FlushEvery: 2},
+		&infras.CSVStream{Header: []string{"id", "name"}, Each: eachRow},
+	})
+
+	rec := httptest.NewRecorder()
+	result.WriteResponse(rec, runtime.JSONProducer())
+	if rec.Header().Get("Content-Type") != "application/x-ndjson" || rec.Body.String() != "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n" || !rec.Flushed {
+		t.Errorf("unexpected NDJSON %v %q", rec.Header(), rec.Body.String())
+	}
+
+	req := httptest.NewRequest("GET", "/users", nil)
+	req.Header.Set("Accept", "text/csv, application/json;q=0.5")
+	rec = httptest.NewRecorder()
+	result.WriteResponse(infras.WithRequest(rec, req), runtime.JSONProducer())
+	if rec.Header().Get("Content-Type") != "text/csv; charset=utf-8" || rec.Body.String() != "id,name\n1,\"user, 1\"\n2,\"user, 2\"\n3,\"user, 3\"\n" {
+		t.Errorf("unexpected CSV %v %q", rec.Header(), rec.Body.String())
+	}
+}
+
+func TestWriteResponseStreamFailure(t *testing.T) {
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Errorf("expected the response to be aborted but got %v", r)
+		}
+	}()
+	infras.OK(&infras.NDJSONStream{Each: func(emit func(item interface{}) error) error {
+		emit(1)
+		return errors.New("cursor closed")
+	}}).WriteResponse(httptest.NewRecorder(), runtime.JSONProducer())
+}
@@ -0,0 +1,77 @@
+package infras
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+)
+
+// requestResponseWriter give the responders access to the request they answer, the optional interfaces
+// of the wrapped writer (Flusher, Hijacker, Pusher, CloseNotifier) are forwarded
+type requestResponseWriter struct {
+	http.ResponseWriter
+	request *http.Request
+}
+
+// WithRequest wrap the writer so WriteResponse can read the request headers and context
+func WithRequest(rw http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	return &requestResponseWriter{rw, r}
+}
+
+// RequestFromResponseWriter return the request carried by a writer from WithRequest, nil otherwise
+func RequestFromResponseWriter(rw http.ResponseWriter) *http.Request {
+	for {
+		switch w := rw.(type) {
+		case *requestResponseWriter:
+			return w.request
+		case interface{ Unwrap() http.ResponseWriter }:
+			rw = w.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+func (w *requestResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap used by http.ResponseController
+func (w *requestResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Hijack used by websocket upgrades, http.ErrNotSupported when the wrapped writer can't be hijacked
+func (w *requestResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Push HTTP/2 server push, http.ErrNotSupported when the wrapped writer can't push
+func (w *requestResponseWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// CloseNotify channel of the wrapped writer, never receiving when it has none
+func (w *requestResponseWriter) CloseNotify() <-chan bool {
+	if n, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return n.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+// requestContext context of the request carried by the writer, see WithRequest
+func requestContext(rw http.ResponseWriter) context.Context {
+	if r := RequestFromResponseWriter(rw); r != nil {
+		return r.Context()
+	}
+	return context.Background()
+}
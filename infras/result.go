@@ -1,7 +1,9 @@
 package infras
 
 import (
+	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/go-openapi/runtime"
 	"google.golang.org/grpc/codes"
@@ -11,49 +13,65 @@ import (
 type Result struct {
 	Value interface{}
 	Error *status.Status
+	Meta  *ResponseMeta // status override, headers and cookies of the HTTP response
+	Cause error         // origin error of the failure for the server logs, never sent to the clients
+}
+
+// errorResponse REST body of failures, ErrorCode is the ErrorInfo reason
+type errorResponse struct {
+	Message         string
+	ErrorCode       string            `json:",omitempty"`
+	FieldViolations []FieldViolation  `json:",omitempty"`
+	Details         []json.RawMessage `json:",omitempty"`
 }
 
 func OK(v interface{}) *Result {
 	return &Result{
-		v,
-		nil,
+		Value: v,
 	}
 }
 
 func Failf(code codes.Code, f string, o ...interface{}) *Result {
 	return &Result{
-		nil,
-		status.Newf(code, f, o...),
+		Error: status.Newf(code, f, o...),
 	}
 }
 func Fail(code codes.Code, m string) *Result {
 	return &Result{
-		nil,
-		status.New(code, m),
+		Error: status.New(code, m),
 	}
 }
 
 // Implement Responder interface (Responder is an interface for types to implement, when they want to be considered for writing HTTP responses)
 func (r *Result) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
 	if r.Error == nil {
-		rw.WriteHeader(200)
-		if err := producer.Produce(rw, r.Value); err != nil {
-			panic(err) // let the recovery middleware deal with this
-		}
+		WriteValue(rw, producer, r.Meta, r.Value)
 	} else {
-		code := r.Error.Code()
-		switch code {
-		case codes.InvalidArgument:
-			rw.WriteHeader(400)
-		case codes.NotFound:
-			rw.WriteHeader(404)
-		case codes.Aborted:
-			rw.WriteHeader(412)
-		default:
-			rw.WriteHeader(500)
+		r.Meta.SetHeaders(rw)
+		setRetryAfter(rw, r.Error)
+		ctx := requestContext(rw)
+		s := RedactStatus(LocalizeStatus(ctx, r.Error))
+		httpStatus := HTTPStatusFromContext(ctx, s.Code())
+		if AcceptsProblem(rw) {
+			WriteProblem(rw, problemFromStatus(httpStatus, s))
+			return
 		}
-		if err := producer.Produce(rw, &struct{ Message string }{Message: r.Error.Message()}); err != nil {
+		rw.WriteHeader(httpStatus)
+		responseMessage := &errorResponse{
+			Message:         s.Message(),
+			FieldViolations: FieldViolationsOf(s),
+			Details:         renderDetails(s),
+		}
+		if info := r.ErrorInfo(); info != nil {
+			responseMessage.ErrorCode = info.Type
+		}
+		if err := producer.Produce(rw, responseMessage); err != nil {
 			panic(err) // let the recovery middleware deal with this
 		}
 	}
 }
+
+// FailWithRetryDelay fail with RetryInfo detail telling the client when to retry
+func FailWithRetryDelay(code codes.Code, m string, retryDelay time.Duration) *Result {
+	return Fail(code, m).WithRetryInfo(retryDelay)
+}
@@ -0,0 +1,128 @@
+package infras
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FailWithDetails fail with google.rpc error details (errdetails.BadRequest, errdetails.ErrorInfo, ...)
+func FailWithDetails(code codes.Code, m string, details ...proto.Message) *Result {
+	return Fail(code, m).WithDetails(details...)
+}
+
+// FromError result of an error returned by a gRPC call, details are kept
+func FromError(err error) *Result {
+	if err == nil {
+		return OK(nil)
+	}
+	s, _ := status.FromError(err)
+	return &Result{
+		Error: s,
+	}
+}
+
+// Err gRPC error of the result with its details, nil on success
+func (r *Result) Err() error {
+	if r.Error == nil {
+		return nil
+	}
+	return r.Error.Err()
+}
+
+// WithDetails copy of the failed result with the details appended, success results are returned as is
+func (r *Result) WithDetails(details ...proto.Message) *Result {
+	if r.Error == nil || len(details) == 0 {
+		return r
+	}
+	s, err := r.Error.WithDetails(details...)
+	if err != nil {
+		// only happen when a detail can't be marshalled, keep the failure without it
+		return r
+	}
+	return r.withStatus(s)
+}
+
+// WithFieldViolation add a violation to the BadRequest detail, the detail is created when missing
+func (r *Result) WithFieldViolation(field, description string) *Result {
+	return r.withBadRequestViolations([]*errdetails.BadRequest_FieldViolation{{Field: field, Description: description}})
+}
+
+// withBadRequestViolations append the violations to the BadRequest detail, encoded once whatever their number
+func (r *Result) withBadRequestViolations(violations []*errdetails.BadRequest_FieldViolation) *Result {
+	if r.Error == nil || len(violations) == 0 {
+		return r
+	}
+	p := r.Error.Proto()
+	for i, d := range p.Details {
+		br := &errdetails.BadRequest{}
+		if !ptypes.Is(d, br) {
+			continue
+		}
+		if err := ptypes.UnmarshalAny(d, br); err != nil {
+			return r
+		}
+		br.FieldViolations = append(br.FieldViolations, violations...)
+		a, err := ptypes.MarshalAny(br)
+		if err != nil {
+			return r
+		}
+		p.Details[i] = a
+		return r.withStatus(status.FromProto(p))
+	}
+	return r.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+}
+
+// WithErrorInfo add the machine readable reason of the failure, reason is an UPPER_SNAKE_CASE error code.
+// The reason is stored in ErrorInfo.Type, the field renamed reason by later google.rpc versions (same field number).
+func (r *Result) WithErrorInfo(reason, domain string, metadata map[string]string) *Result {
+	return r.WithDetails(&errdetails.ErrorInfo{Type: reason, Domain: domain, Metadata: metadata})
+}
+
+// WithRetryInfo tell the client how long to wait before retrying
+func (r *Result) WithRetryInfo(retryDelay time.Duration) *Result {
+	return r.WithDetails(&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(retryDelay)})
+}
+
+// ErrorInfo return the ErrorInfo detail of the failure, nil when there is none
+func (r *Result) ErrorInfo() *errdetails.ErrorInfo {
+	if r.Error == nil {
+		return nil
+	}
+	for _, d := range r.Error.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	return nil
+}
+
+func (r *Result) withStatus(s *status.Status) *Result {
+	c := *r
+	c.Error = s
+	return &c
+}
+
+// renderDetails details as JSON objects with their "@type", as grpc-gateway renders them. BadRequest is
+// left out, its violations are written as the field violations of the body
+func renderDetails(s *status.Status) []json.RawMessage {
+	var rendered []json.RawMessage
+	m := &jsonpb.Marshaler{}
+	for _, d := range s.Proto().Details {
+		if ptypes.Is(d, &errdetails.BadRequest{}) {
+			continue
+		}
+		str, err := m.MarshalToString(d)
+		if err != nil {
+			continue
+		}
+		rendered = append(rendered, json.RawMessage(str))
+	}
+	return rendered
+}
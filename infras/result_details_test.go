@@ -0,0 +1,118 @@
+package infras_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/golang/protobuf/proto"
+	"github.com/jedrp/go-core/infras"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResultDetailsRoundTrip(t *testing.T) {
+	r := infras.Fail(codes.InvalidArgument, "invalid user").
+		WithFieldViolation("name", "is required").
+		WithFieldViolation("email", "is not an email").
+		WithErrorInfo("USER_INVALID", "users.example.com", map[string]string{"id": "1"})
+
+	// what a gRPC client receive
+	b, err := proto.Marshal(r.Error.Proto())
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := &spb.Status{}
+	if err := proto.Unmarshal(b, received); err != nil {
+		t.Fatal(err)
+	}
+	res := infras.FromError(status.FromProto(received).Err())
+
+	details := res.Error.Details()
+	if len(details) != 2 {
+		t.Fatalf("expected BadRequest and ErrorInfo details but got %v", details)
+	}
+	br, ok := details[0].(*errdetails.BadRequest)
+	if !ok || len(br.FieldViolations) != 2 || br.FieldViolations[1].Field != "email" {
+		t.Errorf("unexpected BadRequest %v", details[0])
+	}
+	info := res.ErrorInfo()
+	if info == nil || info.Type != "USER_INVALID" || info.Metadata["id"] != "1" {
+		t.Errorf("unexpected ErrorInfo %v", info)
+	}
+}
+
+func TestWriteResponseDetails(t *testing.T) {
+	r := infras.FailWithDetails(codes.NotFound, "user 1 not found", &errdetails.ErrorInfo{Type: "USER_NOT_FOUND", Domain: "users"})
+
+	rec := httptest.NewRecorder()
+	r.WriteResponse(rec, runtime.JSONProducer())
+
+	var body struct {
+		Message   string
+		ErrorCode string
+		Details   []map[string]interface{}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.ErrorCode != "USER_NOT_FOUND" {
+		t.Errorf("expected error code from ErrorInfo but got %q", body.ErrorCode)
+	}
+	if len(body.Details) != 1 || body.Details[0]["@type"] != "type.googleapis.com/google.rpc.ErrorInfo" {
+		t.Errorf("unexpected details %s", rec.Body.String())
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	v := &infras.ValidationErrors{}
+	v.Add("name", "REQUIRED", "name is required").Add("address.zip", "INVALID_ZIP", "zip is not valid")
+	if !v.HasViolations() || v.Error() != "name: name is required; address.zip: zip is not valid" {
+		t.Errorf("unexpected violations %v", v.Error())
+	}
+
+	rec := httptest.NewRecorder()
+	v.Result("invalid user").WriteResponse(rec, runtime.JSONProducer())
+	var body struct {
+		Message         string
+		FieldViolations []infras.FieldViolation
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != 400 || len(body.FieldViolations) != 2 || body.FieldViolations[1].Path != "address.zip" || body.FieldViolations[1].Message != "zip is not valid" {
+		t.Errorf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+	if violations := infras.FieldViolationsOf(v.Result("invalid user").Error); len(violations) != 2 {
+		t.Errorf("expected violations in a single BadRequest but got %v", violations)
+	}
+}
+
+func TestValidationErrorsProblem(t *testing.T) {
+	v := &infras.ValidationErrors{}
+	for i := 0; i < 3; i++ {
+		v.Add("items["+strconv.Itoa(i)+"].quantity", "POSITIVE", "quantity must be positive")
+	}
+	req := httptest.NewRequest("POST", "/orders", nil)
+	req.Header.Set("Accept", infras.ProblemContentType)
+	rec := httptest.NewRecorder()
+	v.Result("invalid order").WithErrorInfo("ORDER_INVALID", "orders", nil).WriteResponse(infras.WithRequest(rec, req), runtime.JSONProducer())
+
+	var body struct {
+		FieldViolations []infras.FieldViolation  `json:"fieldViolations"`
+		Details         []map[string]interface{} `json:"details"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.FieldViolations) != 3 {
+		t.Errorf("expected 3 field violations but got %s", rec.Body.String())
+	}
+	if len(body.Details) != 1 || body.Details[0]["@type"] != "type.googleapis.com/google.rpc.ErrorInfo" {
+		t.Errorf("expected the violations written once, without BadRequest detail, but got %s", rec.Body.String())
+	}
+}
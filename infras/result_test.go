@@ -0,0 +1,132 @@
+package infras_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/jedrp/go-core/infras"
+	"github.com/jedrp/go-core/pllog"
+	"google.golang.org/grpc/codes"
+)
+
+func TestWriteResponseStatus(t *testing.T) {
+	tt := []struct {
+		result     *infras.Result
+		expected   int
+		retryAfter string
+	}{
+		{infras.OK(1), 200, ""},
+		{infras.Fail(codes.InvalidArgument, "bad"), 400, ""},
+		{infras.Fail(codes.Unauthenticated, "who"), 401, ""},
+		{infras.Fail(codes.PermissionDenied, "no"), 403, ""},
+		{infras.Fail(codes.NotFound, "missing"), 404, ""},
+		{infras.Fail(codes.AlreadyExists, "dup"), 409, ""},
+		{infras.Fail(codes.Aborted, "concurrent update"), 412, ""},
+		{infras.Fail(codes.FailedPrecondition, "state"), 400, ""},
+		{infras.Fail(codes.ResourceExhausted, "slow down"), 429, ""},
+		{infras.FailWithRetryDelay(codes.ResourceExhausted, "slow down", 1500*time.Millisecond), 429, "2"},
+		{infras.FailWithRetryDelay(codes.Unavailable, "maintenance", time.Minute), 503, "60"},
+		{infras.Fail(codes.Canceled, "gone"), 499, ""},
+		{infras.Fail(codes.DeadlineExceeded, "late"), 504, ""},
+		{infras.Fail(codes.Unimplemented, "todo"), 501, ""},
+		{infras.Fail(codes.Internal, "boom"), 500, ""},
+	}
+
+	for _, tc := range tt {
+		rec := httptest.NewRecorder()
+		tc.result.WriteResponse(rec, runtime.JSONProducer())
+		if rec.Code != tc.expected {
+			t.Errorf("%v expected status %d but got %d", tc.result.Error, tc.expected, rec.Code)
+		}
+		if rec.Header().Get("Retry-After") != tc.retryAfter {
+			t.Errorf("%v expected Retry-After %q but got %q", tc.result.Error, tc.retryAfter, rec.Header().Get("Retry-After"))
+		}
+	}
+}
+
+func TestHTTPStatusOverrides(t *testing.T) {
+	req := httptest.NewRequest("GET", "/orders/1", nil)
+	req = req.WithContext(infras.ContextWithHTTPStatus(req.Context(), infras.HTTPStatusOverrides{codes.FailedPrecondition: 422}))
+
+	rec := httptest.NewRecorder()
+	infras.Fail(codes.FailedPrecondition, "state").WriteResponse(infras.WithRequest(rec, req), runtime.JSONProducer())
+	if rec.Code != 422 {
+		t.Errorf("expected overridden status 422 but got %d", rec.Code)
+	}
+
+	// other services keep the default mapping
+	rec = httptest.NewRecorder()
+	infras.Fail(codes.FailedPrecondition, "state").WriteResponse(rec, runtime.JSONProducer())
+	if rec.Code != 400 {
+		t.Errorf("expected default status 400 but got %d", rec.Code)
+	}
+}
+
+func TestWriteResponseProblem(t *testing.T) {
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set("Accept", "application/json, application/problem+json")
+	req = req.WithContext(context.WithValue(req.Context(), pllog.RequestID, "req-1"))
+
+	rec := httptest.NewRecorder()
+	infras.Fail(codes.NotFound, "user 1 not found").WriteResponse(infras.WithRequest(rec, req), runtime.JSONProducer())
+
+	if rec.Code != 404 {
+		t.Errorf("expected 404 but got %d", rec.Code)
+	}
+	if rec.Header().Get("Content-Type") != infras.ProblemContentType {
+		t.Errorf("expected problem content type but got %s", rec.Header().Get("Content-Type"))
+	}
+	var body map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	expected := map[string]interface{}{
+		"type":      "urn:problem-type:not-found",
+		"title":     "Not Found",
+		"status":    float64(404),
+		"detail":    "user 1 not found",
+		"instance":  "req-1",
+		"errorCode": "NOT_FOUND",
+	}
+	for k, v := range expected {
+		if body[k] != v {
+			t.Errorf("expected %s to be %v but got %v", k, v, body[k])
+		}
+	}
+
+	// clients not asking for problem details keep the current body
+	rec = httptest.NewRecorder()
+	infras.Fail(codes.NotFound, "user 1 not found").WriteResponse(infras.WithRequest(rec, httptest.NewRequest("GET", "/users/1", nil)), runtime.JSONProducer())
+	if strings.TrimSpace(rec.Body.String()) != `{"Message":"user 1 not found"}` {
+		t.Errorf("unexpected legacy body %s", rec.Body.String())
+	}
+}
+
+func TestWriteResponseRedacted(t *testing.T) {
+	r := infras.Fail(codes.AlreadyExists, "user jed@example.com already exists")
+	rec := httptest.NewRecorder()
+	r.WriteResponse(rec, runtime.JSONProducer())
+	if strings.Contains(rec.Body.String(), "jed@example.com") {
+		t.Errorf("expected the email to be redacted but got %s", rec.Body.String())
+	}
+	if r.Error.Message() != "user jed@example.com already exists" {
+		t.Errorf("the result should keep the original message but got %s", r.Error.Message())
+	}
+
+	r = infras.Fail(codes.InvalidArgument, "invalid user").
+		WithErrorInfo("USER_INVALID", "users", map[string]string{"owner": "jed@example.com", "param.token": "abc123"}).
+		WithFieldViolation("email", "jed@example.com is taken")
+	s := infras.RedactStatus(r.Error)
+	for _, leaked := range []string{"jed@example.com", "abc123"} {
+		if strings.Contains(fmt.Sprint(s.Details()), leaked) {
+			t.Errorf("expected %s to be redacted from the details but got %v", leaked, s.Details())
+		}
+	}
+	if len(s.Details()) != 2 || len(infras.FieldViolationsOf(s)) != 1 {
+		t.Errorf("expected the details kept but got %v", s.Details())
+	}
+}
@@ -0,0 +1,76 @@
+package infras
+
+import (
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FieldViolation invalid field of a request, Path in dotted notation (address.zip, items[2].quantity).
+// BadRequest has no code, over gRPC the violation is only the path and the message
+type FieldViolation struct {
+	Path    string `json:"path"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors accumulate the field violations of a request to report them all at once:
+//
+//	v := &infras.ValidationErrors{}
+//	if req.Name == "" {
+//		v.Add("name", "REQUIRED", "name is required")
+//	}
+//	if v.HasViolations() {
+//		return v.Result("invalid user")
+//	}
+type ValidationErrors struct {
+	Violations []FieldViolation
+}
+
+// Add a violation of the field
+func (v *ValidationErrors) Add(path, code, message string) *ValidationErrors {
+	v.Violations = append(v.Violations, FieldViolation{Path: path, Code: code, Message: message})
+	return v
+}
+
+// HasViolations true when at least one violation was added
+func (v *ValidationErrors) HasViolations() bool {
+	return len(v.Violations) > 0
+}
+
+func (v *ValidationErrors) Error() string {
+	messages := make([]string, len(v.Violations))
+	for i, violation := range v.Violations {
+		messages[i] = violation.Path + ": " + violation.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Result InvalidArgument failure with every violation in the BadRequest detail
+func (v *ValidationErrors) Result(message string) *Result {
+	return Fail(codes.InvalidArgument, message).WithFieldViolations(v.Violations...)
+}
+
+// WithFieldViolations add the violations to the BadRequest detail, see WithFieldViolation
+func (r *Result) WithFieldViolations(violations ...FieldViolation) *Result {
+	fieldViolations := make([]*errdetails.BadRequest_FieldViolation, len(violations))
+	for i, violation := range violations {
+		fieldViolations[i] = &errdetails.BadRequest_FieldViolation{Field: violation.Path, Description: violation.Message}
+	}
+	return r.withBadRequestViolations(fieldViolations)
+}
+
+// FieldViolationsOf violations of the BadRequest details of the status
+func FieldViolationsOf(s *status.Status) []FieldViolation {
+	var violations []FieldViolation
+	for _, d := range s.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.FieldViolations {
+				violations = append(violations, FieldViolation{Path: v.Field, Message: v.Description})
+			}
+		}
+	}
+	return violations
+}
@@ -1,13 +1,38 @@
 package pllog
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"log"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/jedrp/go-core/redact"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// TextFormat console logs as text lines of the log package
+	TextFormat = "text"
+	// JSONFormat console logs as JSON lines for the log shippers
+	JSONFormat = "json"
+)
+
+// DefaultLogger console logger, Format is TextFormat (default) or JSONFormat and Out the writer,
+// the output of the log package when nil. Levels replaces Level when set
 type DefaultLogger struct {
-	Level logrus.Level
+	Level  logrus.Level
+	Format string
+	Out    io.Writer
+	Levels *LevelController
+	mu     sync.Mutex
 }
 
 func NewDefaultLogger(l logrus.Level) *DefaultLogger {
@@ -16,70 +41,270 @@ func NewDefaultLogger(l logrus.Level) *DefaultLogger {
 	}
 }
 
+// ValidateLogFormat error for formats other than TextFormat and JSONFormat, empty is TextFormat
+func ValidateLogFormat(format string) error {
+	switch format {
+	case "", TextFormat, JSONFormat:
+		return nil
+	}
+	return fmt.Errorf("not a valid log format: %q", format)
+}
+
 func (l *DefaultLogger) Trace(args ...interface{}) {
-	l.Log(logrus.TraceLevel, args...)
+	l.write(logrus.TraceLevel, nil, sprintln(args...))
 }
 func (l *DefaultLogger) Debug(args ...interface{}) {
-	l.Log(logrus.DebugLevel, args...)
+	l.write(logrus.DebugLevel, nil, sprintln(args...))
 }
 func (l *DefaultLogger) Info(args ...interface{}) {
-	l.Log(logrus.InfoLevel, args...)
+	l.write(logrus.InfoLevel, nil, sprintln(args...))
 }
 func (l *DefaultLogger) Warn(args ...interface{}) {
-	l.Log(logrus.WarnLevel, args...)
+	l.write(logrus.WarnLevel, nil, sprintln(args...))
 }
 func (l *DefaultLogger) Error(args ...interface{}) {
-	l.Log(logrus.ErrorLevel, args...)
+	l.write(logrus.ErrorLevel, nil, sprintln(args...))
 }
 func (l *DefaultLogger) Fatal(args ...interface{}) {
-	l.Log(logrus.FatalLevel, args...)
+	l.write(logrus.FatalLevel, nil, sprintln(args...))
 }
 func (l *DefaultLogger) Panic(args ...interface{}) {
-	l.Log(logrus.PanicLevel, args...)
+	l.write(logrus.PanicLevel, nil, sprintln(args...))
 }
 func (l *DefaultLogger) Tracef(format string, args ...interface{}) {
-	l.Logf(logrus.TraceLevel, format, args...)
+	l.write(logrus.TraceLevel, nil, fmt.Sprintf(format, args...))
 }
 func (l *DefaultLogger) Debugf(format string, args ...interface{}) {
-	l.Logf(logrus.DebugLevel, format, args...)
+	l.write(logrus.DebugLevel, nil, fmt.Sprintf(format, args...))
 }
 func (l *DefaultLogger) Infof(format string, args ...interface{}) {
-	l.Logf(logrus.InfoLevel, format, args...)
+	l.write(logrus.InfoLevel, nil, fmt.Sprintf(format, args...))
 }
 func (l *DefaultLogger) Warnf(format string, args ...interface{}) {
-	l.Logf(logrus.WarnLevel, format, args...)
+	l.write(logrus.WarnLevel, nil, fmt.Sprintf(format, args...))
 }
 func (l *DefaultLogger) Errorf(format string, args ...interface{}) {
-	l.Logf(logrus.ErrorLevel, format, args...)
+	l.write(logrus.ErrorLevel, nil, fmt.Sprintf(format, args...))
 }
 func (l *DefaultLogger) Fatalf(format string, args ...interface{}) {
-	l.Logf(logrus.FatalLevel, format, args...)
+	l.write(logrus.FatalLevel, nil, fmt.Sprintf(format, args...))
 }
 func (l *DefaultLogger) Panicf(format string, args ...interface{}) {
-	l.Logf(logrus.PanicLevel, format, args...)
+	l.write(logrus.PanicLevel, nil, fmt.Sprintf(format, args...))
 }
 func (l *DefaultLogger) WithFields(fields map[string]interface{}) PlLogentry {
-	return l
+	return l.entry().WithFields(fields)
+}
+
+func (l *DefaultLogger) WithField(key string, value interface{}) PlLogentry {
+	return l.entry().WithField(key, value)
+}
+
+func (l *DefaultLogger) WithError(err error) PlLogentry {
+	return l.entry().WithError(err)
+}
+
+// WithContext entry with the request and correlation ids of the context
+func (l *DefaultLogger) WithContext(ctx context.Context) PlLogentry {
+	return l.entry().WithContext(ctx)
+}
+
+// LevelController runtime levels of the logger, nil when Level is fixed
+func (l *DefaultLogger) LevelController() *LevelController {
+	return l.Levels
+}
+
+// Module entry of the module, filtered by the module level
+func (l *DefaultLogger) Module(name string) PlLogentry {
+	return l.entry().WithField(ModuleKey, name)
+}
+
+func (l *DefaultLogger) entry() *defaultEntry {
+	return &defaultEntry{logger: l}
 }
 
 func (l *DefaultLogger) Logf(level logrus.Level, format string, args ...interface{}) {
-	if l.IsLevelEnabled(level) {
-		log.Printf(format, args...)
-		if level <= logrus.PanicLevel {
-			panic(l)
+	l.write(level, nil, fmt.Sprintf(format, args...))
+}
+
+func (l *DefaultLogger) Log(level logrus.Level, args ...interface{}) {
+	l.write(level, nil, sprintln(args...))
+}
+
+// write the entry, Fatal exit the process and Panic panic with the message after writing.
+// write is only called by the methods of the loggers so the caller is always the frame above them
+func (l *DefaultLogger) write(level logrus.Level, fields map[string]interface{}, msg string) {
+	if l.enabled(fields, level) {
+		msg = redact.String(msg)
+		if l.Format == JSONFormat {
+			l.writeJSON(level, fields, msg)
+		} else if l.Out != nil {
+			l.mu.Lock()
+			fmt.Fprintf(l.Out, "%s %s%s\n", time.Now().Format("2006/01/02 15:04:05"), msg, formatFields(fields))
+			l.mu.Unlock()
+		} else {
+			log.Print(msg + formatFields(fields))
 		}
 	}
+	switch level {
+	case logrus.FatalLevel:
+		exit(1)
+	case logrus.PanicLevel:
+		panic(msg)
+	}
 }
 
-func (l *DefaultLogger) Log(level logrus.Level, args ...interface{}) {
-	if l.IsLevelEnabled(level) {
-		log.Println(args...)
-		if level <= logrus.PanicLevel {
-			panic(l)
+// writeJSON one JSON object by line with time, level, msg, caller and the fields,
+// fields named as the entry keys are prefixed by fields. as logrus does
+func (l *DefaultLogger) writeJSON(level logrus.Level, fields map[string]interface{}, msg string) {
+	entry := make(map[string]interface{}, len(fields)+4)
+	for k, v := range fields {
+		switch k {
+		case "time", "level", "msg", "caller":
+			k = "fields." + k
+		}
+		if err, ok := v.(error); ok {
+			v = err.Error()
 		}
+		entry[k] = v
 	}
+	entry["time"] = time.Now().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["msg"] = msg
+	if _, file, line, ok := runtime.Caller(3); ok {
+		entry["caller"] = filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		b, _ = json.Marshal(map[string]interface{}{"time": entry["time"], "level": entry["level"], "msg": msg, "error": "fields are not serializable: " + err.Error()})
+	}
+	out := l.Out
+	if out == nil {
+		out = log.Writer()
+	}
+	l.mu.Lock()
+	out.Write(append(b, '\n'))
+	l.mu.Unlock()
 }
 
 func (l *DefaultLogger) IsLevelEnabled(level logrus.Level) bool {
+	if l.Levels != nil {
+		return l.Levels.Level() >= level
+	}
 	return l.Level >= level
 }
+
+// enabled level of the module of the fields when the levels are dynamic
+func (l *DefaultLogger) enabled(fields map[string]interface{}, level logrus.Level) bool {
+	if l.Levels == nil {
+		return l.Level >= level
+	}
+	module, _ := fields[ModuleKey].(string)
+	return l.Levels.Enabled(module, level)
+}
+
+func sprintln(args ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+func formatFields(fields map[string]interface{}) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		v := fmt.Sprint(fields[k])
+		if strings.ContainsAny(v, " =\"\t\n") || v == "" {
+			v = strconv.Quote(v)
+		}
+		b.WriteString(" " + k + "=" + v)
+	}
+	return b.String()
+}
+
+// defaultEntry DefaultLogger entry, fields are redacted when added
+type defaultEntry struct {
+	logger *DefaultLogger
+	fields map[string]interface{}
+}
+
+func (e *defaultEntry) WithFields(fields map[string]interface{}) PlLogentry {
+	merged := make(map[string]interface{}, len(e.fields)+len(fields))
+	for k, v := range e.fields {
+		merged[k] = v
+	}
+	for k, v := range redact.Fields(fields) {
+		merged[k] = v
+	}
+	return &defaultEntry{logger: e.logger, fields: merged}
+}
+
+func (e *defaultEntry) WithField(key string, value interface{}) PlLogentry {
+	return e.WithFields(map[string]interface{}{key: value})
+}
+
+func (e *defaultEntry) WithError(err error) PlLogentry {
+	return e.WithField(ErrorKey, err)
+}
+
+func (e *defaultEntry) WithContext(ctx context.Context) PlLogentry {
+	return e.WithFields(contextFields(ctx))
+}
+
+func (e *defaultEntry) Trace(args ...interface{}) {
+	e.logger.write(logrus.TraceLevel, e.fields, sprintln(args...))
+}
+
+func (e *defaultEntry) Debug(args ...interface{}) {
+	e.logger.write(logrus.DebugLevel, e.fields, sprintln(args...))
+}
+
+func (e *defaultEntry) Info(args ...interface{}) {
+	e.logger.write(logrus.InfoLevel, e.fields, sprintln(args...))
+}
+
+func (e *defaultEntry) Warn(args ...interface{}) {
+	e.logger.write(logrus.WarnLevel, e.fields, sprintln(args...))
+}
+
+func (e *defaultEntry) Error(args ...interface{}) {
+	e.logger.write(logrus.ErrorLevel, e.fields, sprintln(args...))
+}
+
+func (e *defaultEntry) Fatal(args ...interface{}) {
+	e.logger.write(logrus.FatalLevel, e.fields, sprintln(args...))
+}
+
+func (e *defaultEntry) Panic(args ...interface{}) {
+	e.logger.write(logrus.PanicLevel, e.fields, sprintln(args...))
+}
+
+func (e *defaultEntry) Tracef(format string, args ...interface{}) {
+	e.logger.write(logrus.TraceLevel, e.fields, fmt.Sprintf(format, args...))
+}
+
+func (e *defaultEntry) Debugf(format string, args ...interface{}) {
+	e.logger.write(logrus.DebugLevel, e.fields, fmt.Sprintf(format, args...))
+}
+
+func (e *defaultEntry) Infof(format string, args ...interface{}) {
+	e.logger.write(logrus.InfoLevel, e.fields, fmt.Sprintf(format, args...))
+}
+
+func (e *defaultEntry) Warnf(format string, args ...interface{}) {
+	e.logger.write(logrus.WarnLevel, e.fields, fmt.Sprintf(format, args...))
+}
+
+func (e *defaultEntry) Errorf(format string, args ...interface{}) {
+	e.logger.write(logrus.ErrorLevel, e.fields, fmt.Sprintf(format, args...))
+}
+
+func (e *defaultEntry) Fatalf(format string, args ...interface{}) {
+	e.logger.write(logrus.FatalLevel, e.fields, fmt.Sprintf(format, args...))
+}
+
+func (e *defaultEntry) Panicf(format string, args ...interface{}) {
+	e.logger.write(logrus.PanicLevel, e.fields, fmt.Sprintf(format, args...))
+}
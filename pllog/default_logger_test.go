@@ -1,8 +1,17 @@
 package pllog
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jedrp/go-core/redact"
 	"github.com/sirupsen/logrus"
 )
 
@@ -44,3 +53,89 @@ func TestDefaultLogLevel(t *testing.T) {
 
 	}
 }
+
+func TestDefaultLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	defer log.SetOutput(os.Stderr)
+	defer log.SetFlags(log.LstdFlags)
+
+	ctx := context.WithValue(context.Background(), RequestID, "req-1")
+	l := NewDefaultLogger(logrus.InfoLevel)
+	entry := l.WithContext(ctx).WithField("user", "jed")
+	entry.WithFields(map[string]interface{}{"age": 42, "password": "p4ss"}).WithError(errors.New("not found")).Errorf("get %s", "user")
+	entry.Info("done")
+	entry.Debug("hidden")
+
+	expected := "get user RequestId=req-1 age=42 error=\"not found\" password=[REDACTED] user=jed\ndone RequestId=req-1 user=jed\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q but got %q", expected, buf.String())
+	}
+}
+
+func TestDefaultLoggerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := &DefaultLogger{Level: logrus.InfoLevel, Format: JSONFormat, Out: &buf}
+
+	ctx := context.WithValue(context.Background(), CorrelationID, "cor-1")
+	l.WithContext(ctx).WithFields(map[string]interface{}{"msg": "field", "token": "t0k"}).WithError(errors.New("not found")).Warn("get user")
+	l.Debug("hidden")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected one JSON line but got %q: %v", buf.String(), err)
+	}
+	expected := map[string]interface{}{
+		"level":         "warning",
+		"msg":           "get user",
+		"fields.msg":    "field",
+		"token":         redact.Mask,
+		"error":         "not found",
+		"CorrelationId": "cor-1",
+	}
+	for k, v := range expected {
+		if entry[k] != v {
+			t.Errorf("expected %s %v but got %v", k, v, entry[k])
+		}
+	}
+	if caller, _ := entry["caller"].(string); !strings.HasPrefix(caller, "default_logger_test.go:") {
+		t.Errorf("expected the caller in the test but got %v", entry["caller"])
+	}
+	if _, err := time.Parse(time.RFC3339Nano, entry["time"].(string)); err != nil {
+		t.Errorf("expected RFC3339 time but got %v", entry["time"])
+	}
+}
+
+func TestDefaultLoggerFatalPanic(t *testing.T) {
+	var buf bytes.Buffer
+	l := &DefaultLogger{Level: logrus.InfoLevel, Format: TextFormat, Out: &buf}
+
+	code := 0
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+	l.Fatal("stopping")
+	if code != 1 || !strings.Contains(buf.String(), "stopping") {
+		t.Errorf("expected the message logged then exit 1 but got %d %q", code, buf.String())
+	}
+
+	defer func() {
+		if r := recover(); r != "broken invariant" {
+			t.Errorf("expected panic with the message but got %v", r)
+		}
+	}()
+	l.Panicf("broken %s", "invariant")
+}
+
+func TestNewWithRefLogFormat(t *testing.T) {
+	l := NewWithRef(&LogrusLogger{LogLevel: "info", LogFormat: JSONFormat})
+	if d, ok := l.(*DefaultLogger); !ok || d.Format != JSONFormat {
+		t.Errorf("expected a JSON DefaultLogger but got %#v", l)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for an unknown log format")
+		}
+	}()
+	NewWithRef(&LogrusLogger{LogLevel: "info", LogFormat: "xml"})
+}
@@ -0,0 +1,454 @@
+package pllog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/sirupsen/logrus"
+)
+
+// DropPolicy what the buffer does with new entries when it is full
+type DropPolicy string
+
+const (
+	// DropNewest discard the new entry, the default
+	DropNewest DropPolicy = "drop-newest"
+	// DropOldest discard the oldest buffered entry to make room for the new one
+	DropOldest DropPolicy = "drop-oldest"
+	// Block wait for room in the buffer, logging slows down to the indexing rate
+	Block DropPolicy = "block"
+)
+
+// ErrHookClosed entries fired after Close
+var ErrHookClosed = errors.New("elastic hook closed")
+
+// ParseDropPolicy drop policy of the name, DropNewest when empty
+func ParseDropPolicy(name string) (DropPolicy, error) {
+	switch p := DropPolicy(name); p {
+	case "":
+		return DropNewest, nil
+	case DropNewest, DropOldest, Block:
+		return p, nil
+	}
+	return "", fmt.Errorf("not a valid drop policy: %q", name)
+}
+
+// BufferOptions of the buffered hooks, zero values take the defaults
+type BufferOptions struct {
+	Size          int           // entries buffered in memory, 10000 by default
+	DropPolicy    DropPolicy    // when the buffer is full, DropNewest by default
+	BatchSize     int           // entries indexed by bulk request, 500 by default
+	FlushInterval time.Duration // max time an entry waits for its batch, 1s by default
+	MaxRetries    int           // retries of a failing batch before it is spooled, 5 by default and none when negative
+	RetryBackoff  time.Duration // wait before the first retry, doubled up to MaxBackoff, 100ms by default
+	MaxBackoff    time.Duration // 10s by default
+	// SpoolDir directory keeping the batches failing after the retries, they are indexed again once
+	// Elasticsearch is back. Batches are dropped when empty
+	SpoolDir      string
+	SpoolMaxBytes int64 // size of the spool beyond which failing batches are dropped, 100MB by default
+	// OnError called with the shipping failures, they are printed by the log package by default.
+	// It must not log to the hooked logger
+	OnError func(err error)
+}
+
+func (o BufferOptions) withDefaults() BufferOptions {
+	if o.Size < 1 {
+		o.Size = 10000
+	}
+	if o.DropPolicy == "" {
+		o.DropPolicy = DropNewest
+	}
+	if o.BatchSize < 1 {
+		o.BatchSize = 500
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = time.Second
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	} else if o.MaxRetries == 0 {
+		o.MaxRetries = 5
+	}
+	if o.RetryBackoff <= 0 {
+		o.RetryBackoff = 100 * time.Millisecond
+	}
+	if o.MaxBackoff < o.RetryBackoff {
+		o.MaxBackoff = 10 * time.Second
+	}
+	if o.SpoolMaxBytes <= 0 {
+		o.SpoolMaxBytes = 100 << 20
+	}
+	if o.OnError == nil {
+		o.OnError = func(err error) {
+			log.Printf("pllog: %v", err)
+		}
+	}
+	return o
+}
+
+// BufferStats counters of a buffered hook
+type BufferStats struct {
+	Buffered int   // entries waiting in memory
+	Sent     int64 // entries indexed
+	Dropped  int64 // entries lost: buffer full, rejected by Elasticsearch or spool full
+	Spooled  int64 // entries written to the spool
+}
+
+// spooledDoc document and its index, one by line in the spool files
+type spooledDoc struct {
+	Index string          `json:"index"`
+	Type  string          `json:"type,omitempty"`
+	Doc   json.RawMessage `json:"doc"`
+}
+
+// shipFunc send the documents, returns the ones worth retrying and the number of documents rejected for good
+type shipFunc func(ctx context.Context, docs []spooledDoc) (retry []spooledDoc, rejected int, err error)
+
+// logBuffer bounded queue of the documents shipped in batches by a single worker
+type logBuffer struct {
+	ship    shipFunc
+	opts    BufferOptions
+	docs    chan spooledDoc
+	flushes chan chan struct{}
+	stop    chan context.Context
+	done    chan struct{}
+	closed  int32
+	sent    int64
+	dropped int64
+	spooled int64
+
+	spoolMu    sync.Mutex
+	spoolBytes int64
+	spoolSeq   int64
+}
+
+func newLogBuffer(ship shipFunc, opts BufferOptions) (*logBuffer, error) {
+	opts = opts.withDefaults()
+	if _, err := ParseDropPolicy(string(opts.DropPolicy)); err != nil {
+		return nil, err
+	}
+	b := &logBuffer{
+		ship:    ship,
+		opts:    opts,
+		docs:    make(chan spooledDoc, opts.Size),
+		flushes: make(chan chan struct{}),
+		stop:    make(chan context.Context, 1),
+		done:    make(chan struct{}),
+	}
+	if opts.SpoolDir != "" {
+		if err := os.MkdirAll(opts.SpoolDir, 0o755); err != nil {
+			return nil, err
+		}
+		for _, f := range b.spoolFiles() {
+			if fi, err := os.Stat(f); err == nil {
+				b.spoolBytes += fi.Size()
+			}
+		}
+	}
+	go b.run()
+	return b, nil
+}
+
+// add queue the document applying the drop policy when the buffer is full
+func (b *logBuffer) add(doc spooledDoc) error {
+	if atomic.LoadInt32(&b.closed) == 1 {
+		atomic.AddInt64(&b.dropped, 1)
+		return ErrHookClosed
+	}
+	select {
+	case b.docs <- doc:
+		return nil
+	default:
+	}
+	switch b.opts.DropPolicy {
+	case Block:
+		select {
+		case b.docs <- doc:
+		case <-b.done:
+			atomic.AddInt64(&b.dropped, 1)
+			return ErrHookClosed
+		}
+	case DropOldest:
+		select {
+		case <-b.docs:
+			atomic.AddInt64(&b.dropped, 1)
+		default:
+		}
+		select {
+		case b.docs <- doc:
+		default:
+			atomic.AddInt64(&b.dropped, 1)
+		}
+	default:
+		atomic.AddInt64(&b.dropped, 1)
+	}
+	return nil
+}
+
+func (b *logBuffer) stats() BufferStats {
+	return BufferStats{
+		Buffered: len(b.docs),
+		Sent:     atomic.LoadInt64(&b.sent),
+		Dropped:  atomic.LoadInt64(&b.dropped),
+		Spooled:  atomic.LoadInt64(&b.spooled),
+	}
+}
+
+// flush index the buffered entries, returns once they are indexed or spooled
+func (b *logBuffer) flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case b.flushes <- ack:
+	case <-b.done:
+		return ErrHookClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close stop accepting entries and index the buffered ones until ctx is done, the rest is spooled
+func (b *logBuffer) close(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&b.closed, 0, 1) {
+		return nil
+	}
+	b.stop <- ctx
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *logBuffer) run() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.opts.FlushInterval)
+	defer ticker.Stop()
+	ctx := context.Background()
+	batch := make([]spooledDoc, 0, b.opts.BatchSize)
+	send := func() {
+		if len(batch) > 0 {
+			b.send(ctx, batch)
+			batch = batch[:0]
+		}
+	}
+	drain := func() {
+		for {
+			select {
+			case doc := <-b.docs:
+				batch = append(batch, doc)
+				if len(batch) == b.opts.BatchSize {
+					send()
+				}
+			default:
+				send()
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case doc := <-b.docs:
+			batch = append(batch, doc)
+			if len(batch) == b.opts.BatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case ack := <-b.flushes:
+			drain()
+			close(ack)
+		case ctx = <-b.stop:
+			drain()
+			return
+		}
+	}
+}
+
+// send index the batch retrying with backoff, the documents still failing are spooled.
+// The spool is replayed after a successful request
+func (b *logBuffer) send(ctx context.Context, batch []spooledDoc) {
+	pending := batch
+	backoff := b.opts.RetryBackoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		pending, err = b.bulk(ctx, pending)
+		if len(pending) == 0 {
+			b.replaySpool(ctx)
+			return
+		}
+		if attempt == b.opts.MaxRetries || ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+		}
+		if backoff *= 2; backoff > b.opts.MaxBackoff {
+			backoff = b.opts.MaxBackoff
+		}
+	}
+	b.opts.OnError(fmt.Errorf("shipping %d log entries failed: %v", len(pending), err))
+	b.spool(pending)
+}
+
+// bulk ship the documents counting the sent and rejected ones
+func (b *logBuffer) bulk(ctx context.Context, docs []spooledDoc) ([]spooledDoc, error) {
+	retry, rejected, err := b.ship(ctx, docs)
+	atomic.AddInt64(&b.sent, int64(len(docs)-len(retry)-rejected))
+	atomic.AddInt64(&b.dropped, int64(rejected))
+	if err != nil && len(retry) == 0 {
+		b.opts.OnError(fmt.Errorf("%d log entries rejected: %v", rejected, err))
+	}
+	return retry, err
+}
+
+// elasticShipFunc index the documents by bulk request, retry all of them when the request failed,
+// the items rejected with 429 or 5xx otherwise. Items rejected for other reasons are dropped
+func elasticShipFunc(client *elastic.Client, onError func(error)) shipFunc {
+	return func(ctx context.Context, docs []spooledDoc) ([]spooledDoc, int, error) {
+		req := client.Bulk()
+		for _, d := range docs {
+			docType := d.Type
+			if docType == "" {
+				docType = "_doc"
+			}
+			req.Add(elastic.NewBulkIndexRequest().Index(d.Index).Type(docType).Doc(d.Doc))
+		}
+		resp, err := req.Do(ctx)
+		if err != nil {
+			return docs, 0, err
+		}
+		var retry []spooledDoc
+		var rejected int
+		var rejection error
+		for i, item := range resp.Items {
+			for _, r := range item {
+				if r == nil || r.Status < 300 {
+					continue
+				}
+				if i < len(docs) && (r.Status == http.StatusTooManyRequests || r.Status >= 500) {
+					retry = append(retry, docs[i])
+					err = fmt.Errorf("status %d", r.Status)
+					continue
+				}
+				rejected++
+				if r.Error != nil {
+					rejection = fmt.Errorf("log entry rejected by elasticsearch: %s: %s", r.Error.Type, r.Error.Reason)
+				}
+			}
+		}
+		if rejection != nil && onError != nil {
+			onError(rejection)
+		}
+		return retry, rejected, err
+	}
+}
+
+// spool write the documents to a new spool file, dropped without spool or when it is full
+func (b *logBuffer) spool(docs []spooledDoc) {
+	if b.opts.SpoolDir == "" {
+		atomic.AddInt64(&b.dropped, int64(len(docs)))
+		return
+	}
+	var sb strings.Builder
+	for _, d := range docs {
+		line, _ := json.Marshal(d)
+		sb.Write(line)
+		sb.WriteByte('\n')
+	}
+	b.spoolMu.Lock()
+	defer b.spoolMu.Unlock()
+	if b.spoolBytes+int64(sb.Len()) > b.opts.SpoolMaxBytes {
+		atomic.AddInt64(&b.dropped, int64(len(docs)))
+		b.opts.OnError(fmt.Errorf("log spool full, %d entries dropped", len(docs)))
+		return
+	}
+	b.spoolSeq++
+	name := filepath.Join(b.opts.SpoolDir, fmt.Sprintf("%020d-%06d.ndjson", time.Now().UnixNano(), b.spoolSeq))
+	if err := os.WriteFile(name, []byte(sb.String()), 0o644); err != nil {
+		atomic.AddInt64(&b.dropped, int64(len(docs)))
+		b.opts.OnError(fmt.Errorf("spooling log entries failed: %v", err))
+		return
+	}
+	b.spoolBytes += int64(sb.Len())
+	atomic.AddInt64(&b.spooled, int64(len(docs)))
+}
+
+// replaySpool index the spool files oldest first, stops at the first failing file
+func (b *logBuffer) replaySpool(ctx context.Context) {
+	if b.opts.SpoolDir == "" {
+		return
+	}
+	b.spoolMu.Lock()
+	defer b.spoolMu.Unlock()
+	for _, name := range b.spoolFiles() {
+		docs, size, err := readSpoolFile(name)
+		if err == nil && len(docs) > 0 {
+			var retry []spooledDoc
+			if retry, err = b.bulk(ctx, docs); err == nil && len(retry) > 0 {
+				err = fmt.Errorf("%d entries not indexed", len(retry))
+			}
+		}
+		if err != nil {
+			b.opts.OnError(fmt.Errorf("replaying log spool %s failed: %v", filepath.Base(name), err))
+			return
+		}
+		os.Remove(name)
+		b.spoolBytes -= size
+	}
+}
+
+func (b *logBuffer) spoolFiles() []string {
+	names, _ := filepath.Glob(filepath.Join(b.opts.SpoolDir, "*.ndjson"))
+	sort.Strings(names)
+	return names
+}
+
+func readSpoolFile(name string) ([]spooledDoc, int64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	var docs []spooledDoc
+	var size int64
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		size += int64(len(scanner.Bytes())) + 1
+		var d spooledDoc
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			continue // skip lines of a file truncated by a crash
+		}
+		docs = append(docs, d)
+	}
+	return docs, size, scanner.Err()
+}
+
+func bufferedFireFunc(entry *logrus.Entry, hook *ElasticHook) error {
+	doc, err := json.Marshal(newMessage(entry, hook.host))
+	if err != nil {
+		return err
+	}
+	return hook.buffer.add(spooledDoc{Index: hook.index(), Type: hook.docType, Doc: doc})
+}
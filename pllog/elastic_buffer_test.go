@@ -0,0 +1,166 @@
+package pllog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/sirupsen/logrus"
+)
+
+// fakeBulk Elasticsearch bulk endpoint answering 503 while down
+type fakeBulk struct {
+	mu       sync.Mutex
+	down     bool
+	failures int // next requests failing with 503
+	messages []string
+}
+
+func (f *fakeBulk) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down || f.failures > 0 {
+		f.failures--
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var items []string
+	scanner := bufio.NewScanner(r.Body)
+	for i := 0; scanner.Scan(); i++ {
+		if i%2 == 1 {
+			var m Message
+			json.Unmarshal(scanner.Bytes(), &m)
+			f.messages = append(f.messages, m.Message)
+			items = append(items, `{"index":{"status":201}}`)
+		}
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write([]byte(`{"took":1,"errors":false,"items":[` + strings.Join(items, ",") + `]}`))
+}
+
+func (f *fakeBulk) received() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.messages...)
+}
+
+func newBufferedTestLogger(t *testing.T, f *fakeBulk, opts BufferOptions) (*logrus.Logger, *ElasticHook) {
+	ts := httptest.NewServer(f)
+	t.Cleanup(ts.Close)
+	client, err := elastic.NewClient(elastic.SetURL(ts.URL), elastic.SetSniff(false), elastic.SetHealthcheck(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.RetryBackoff = time.Millisecond
+	opts.OnError = func(error) {}
+	hook, err := NewBufferedElasticHookWithFunc(client, "test", logrus.InfoLevel, func() string { return "logs" }, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := logrus.New()
+	log.Out = &strings.Builder{}
+	log.Hooks.Add(hook)
+	return log, hook
+}
+
+func TestBufferedElasticHookRetry(t *testing.T) {
+	f := &fakeBulk{failures: 2}
+	log, hook := newBufferedTestLogger(t, f, BufferOptions{MaxRetries: 3})
+
+	log.Info("first")
+	log.Info("second")
+	if err := hook.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.received(); len(got) != 2 || got[0] != "first" || got[1] != "second" {
+		t.Errorf("expected both entries indexed after the retries but got %v", got)
+	}
+	if s := hook.Stats(); s.Sent != 2 || s.Dropped != 0 {
+		t.Errorf("expected 2 sent but got %+v", s)
+	}
+}
+
+func TestBufferedElasticHookDropPolicy(t *testing.T) {
+	tt := []struct {
+		policy   DropPolicy
+		expected []string
+	}{
+		{policy: DropNewest, expected: []string{"1", "2"}},
+		{policy: DropOldest, expected: []string{"3", "4"}},
+	}
+	for _, tc := range tt {
+		f := &fakeBulk{}
+		log, hook := newBufferedTestLogger(t, f, BufferOptions{Size: 2, DropPolicy: tc.policy, FlushInterval: time.Hour})
+		// keep the worker busy on a flush so the entries stay in the buffer
+		hook.buffer.add(spooledDoc{})
+		f.mu.Lock()
+		go hook.Flush(context.Background())
+		time.Sleep(20 * time.Millisecond)
+		for _, msg := range []string{"1", "2", "3", "4"} {
+			log.Info(msg)
+		}
+		f.mu.Unlock()
+		hook.Close(context.Background())
+
+		got := f.received()
+		if len(got) != 3 || got[1] != tc.expected[0] || got[2] != tc.expected[1] {
+			t.Errorf("%s expected %v indexed but got %v", tc.policy, tc.expected, got)
+		}
+		if s := hook.Stats(); s.Dropped != 2 {
+			t.Errorf("%s expected 2 dropped but got %+v", tc.policy, s)
+		}
+	}
+}
+
+func TestBufferedElasticHookSpool(t *testing.T) {
+	dir := t.TempDir()
+	f := &fakeBulk{down: true}
+	log, hook := newBufferedTestLogger(t, f, BufferOptions{MaxRetries: 1, SpoolDir: dir})
+	log.Info("while down")
+	if err := hook.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	log.Info("after close")
+	if s := hook.Stats(); s.Spooled != 1 || s.Dropped != 1 {
+		t.Errorf("expected 1 spooled and 1 dropped after close but got %+v", s)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.ndjson")); len(files) != 1 {
+		t.Fatalf("expected a spool file but got %v", files)
+	}
+
+	// a new process replays the spool once elasticsearch is back
+	f = &fakeBulk{}
+	log, hook = newBufferedTestLogger(t, f, BufferOptions{SpoolDir: dir})
+	log.Info("back")
+	hook.Flush(context.Background())
+	if got := f.received(); len(got) != 2 || got[0] != "back" || got[1] != "while down" {
+		t.Errorf("expected the spooled entry replayed but got %v", got)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.ndjson")); len(files) != 0 {
+		t.Errorf("expected the spool emptied but got %v", files)
+	}
+}
+
+func TestNewWithRefElasticUnreachable(t *testing.T) {
+	dir := t.TempDir()
+	l := NewWithRef(&LogrusLogger{LogLevel: "info", Enable: true, ElasticHostURL: "http://127.0.0.1:1", LogSpoolDir: dir, Out: &strings.Builder{}})
+	logger, ok := l.(*LogrusLogger)
+	if !ok || len(logger.hooks) != 1 {
+		t.Fatalf("expected a logrus logger with the elastic hook but got %#v", l)
+	}
+	logger.Info("spooled until elasticsearch is back")
+	if err := Close(context.Background(), logger); err != nil {
+		t.Error(err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.ndjson")); len(files) != 1 {
+		t.Errorf("expected the entry spooled while elasticsearch is down at startup but got %v", files)
+	}
+}
@@ -30,9 +30,12 @@ type ElasticHook struct {
 	ctx       context.Context
 	ctxCancel context.CancelFunc
 	fireFunc  fireFunc
+	buffer    *logBuffer
+	docType   string
 }
 
-type message struct {
+// Message document of the log entries shipped by the hooks
+type Message struct {
 	Host      string        `json:"host"`
 	Timestamp string        `json:"@timestamp"`
 	Message   string        `json:"message"`
@@ -49,7 +52,8 @@ func NewElasticHook(client *elastic.Client, host string, level logrus.Level, ind
 	return NewElasticHookWithFunc(client, host, level, func() string { return index })
 }
 
-// NewAsyncElasticHook creates new  hook with asynchronous log.
+// NewAsyncElasticHook creates new  hook with asynchronous log, entries are buffered and
+// indexed in batches with the default BufferOptions.
 // client - ElasticSearch client with specific es version (v5/v6/v7/...)
 // host - host of system
 // level - log level
@@ -58,7 +62,8 @@ func NewAsyncElasticHook(client *elastic.Client, host string, level logrus.Level
 	return NewAsyncElasticHookWithFunc(client, host, level, func() string { return index })
 }
 
-// NewBulkProcessorElasticHook creates new hook that uses a bulk processor for indexing.
+// NewBulkProcessorElasticHook creates new hook that uses a bulk processor for indexing,
+// same as NewAsyncElasticHook.
 // client - ElasticSearch client with specific es version (v5/v6/v7/...)
 // host - host of system
 // level - log level
@@ -86,7 +91,16 @@ func NewElasticHookWithFunc(client *elastic.Client, host string, level logrus.Le
 // level - log level
 // indexFunc - function providing the name of index
 func NewAsyncElasticHookWithFunc(client *elastic.Client, host string, level logrus.Level, indexFunc IndexNameFunc) (*ElasticHook, error) {
-	return newHookFuncAndFireFunc(client, host, level, indexFunc, asyncFireFunc)
+	hook, err := newHookFuncAndFireFunc(client, host, level, indexFunc, bufferedFireFunc)
+	if err != nil {
+		return nil, err
+	}
+	opts := BufferOptions{}.withDefaults()
+	if hook.buffer, err = newLogBuffer(elasticShipFunc(client, opts.OnError), opts); err != nil {
+		hook.Cancel()
+		return nil, err
+	}
+	return hook, nil
 }
 
 // NewBulkProcessorElasticHookWithFunc creates new hook with
@@ -98,14 +112,36 @@ func NewAsyncElasticHookWithFunc(client *elastic.Client, host string, level logr
 // level - log level
 // indexFunc - function providing the name of index
 func NewBulkProcessorElasticHookWithFunc(client *elastic.Client, host string, level logrus.Level, indexFunc IndexNameFunc) (*ElasticHook, error) {
-	fireFunc, err := makeBulkFireFunc(client)
+	return NewAsyncElasticHookWithFunc(client, host, level, indexFunc)
+}
+
+// NewBufferedElasticHookWithFunc creates new hook buffering the entries in memory, they are indexed
+// in batches retried with backoff then spooled to disk while Elasticsearch is down, see BufferOptions.
+// The index is not checked so the hook can be created while Elasticsearch is unreachable,
+// it is created by the first bulk request when missing, see IndexLifecycle for the mappings.
+// Documents are indexed without type (_doc) unlike the other hooks indexing log documents.
+// Call Flush or Close before the process exits
+func NewBufferedElasticHookWithFunc(client *elastic.Client, host string, level logrus.Level, indexFunc IndexNameFunc, opts BufferOptions) (*ElasticHook, error) {
+	opts = opts.withDefaults()
+	buffer, err := newLogBuffer(elasticShipFunc(client, opts.OnError), opts)
 	if err != nil {
 		return nil, err
 	}
-	return newHookFuncAndFireFunc(client, host, level, indexFunc, fireFunc)
+	ctx, cancel := context.WithCancel(context.TODO())
+	return &ElasticHook{
+		client:    client,
+		host:      host,
+		index:     indexFunc,
+		levels:    hookLevels(level),
+		ctx:       ctx,
+		ctxCancel: cancel,
+		fireFunc:  bufferedFireFunc,
+		buffer:    buffer,
+		docType:   "_doc",
+	}, nil
 }
 
-func newHookFuncAndFireFunc(client *elastic.Client, host string, level logrus.Level, indexFunc IndexNameFunc, fireFunc fireFunc) (*ElasticHook, error) {
+func hookLevels(level logrus.Level) []logrus.Level {
 	var levels []logrus.Level
 	for _, l := range []logrus.Level{
 		logrus.PanicLevel,
@@ -120,7 +156,10 @@ func newHookFuncAndFireFunc(client *elastic.Client, host string, level logrus.Le
 			levels = append(levels, l)
 		}
 	}
+	return levels
+}
 
+func newHookFuncAndFireFunc(client *elastic.Client, host string, level logrus.Level, indexFunc IndexNameFunc, fireFunc fireFunc) (*ElasticHook, error) {
 	ctx, cancel := context.WithCancel(context.TODO())
 
 	// Use the IndexExists service to check if a specified index exists.
@@ -146,10 +185,11 @@ func newHookFuncAndFireFunc(client *elastic.Client, host string, level logrus.Le
 		client:    client,
 		host:      host,
 		index:     indexFunc,
-		levels:    levels,
+		levels:    hookLevels(level),
 		ctx:       ctx,
 		ctxCancel: cancel,
 		fireFunc:  fireFunc,
+		docType:   "log",
 	}, nil
 }
 
@@ -159,12 +199,11 @@ func (hook *ElasticHook) Fire(entry *logrus.Entry) error {
 	return hook.fireFunc(entry, hook)
 }
 
-func asyncFireFunc(entry *logrus.Entry, hook *ElasticHook) error {
-	go syncFireFunc(entry, hook)
-	return nil
+func createMessage(entry *logrus.Entry, hook *ElasticHook) *Message {
+	return newMessage(entry, hook.host)
 }
 
-func createMessage(entry *logrus.Entry, hook *ElasticHook) *message {
+func newMessage(entry *logrus.Entry, host string) *Message {
 	level := entry.Level.String()
 
 	if e, ok := entry.Data[logrus.ErrorKey]; ok && e != nil {
@@ -173,8 +212,8 @@ func createMessage(entry *logrus.Entry, hook *ElasticHook) *message {
 		}
 	}
 
-	return &message{
-		hook.host,
+	return &Message{
+		host,
 		entry.Time.UTC().Format(time.RFC3339Nano),
 		entry.Message,
 		entry.Data,
@@ -186,31 +225,13 @@ func syncFireFunc(entry *logrus.Entry, hook *ElasticHook) error {
 	_, err := hook.client.
 		Index().
 		Index(hook.index()).
-		Type("log").
+		Type(hook.docType).
 		BodyJson(*createMessage(entry, hook)).
 		Do(hook.ctx)
 
 	return err
 }
 
-// Create closure with bulk processor tied to fireFunc.
-func makeBulkFireFunc(client *elastic.Client) (fireFunc, error) {
-	processor, err := client.BulkProcessor().
-		Name("elogrus.v3.bulk.processor").
-		Workers(2).
-		FlushInterval(time.Second).
-		Do(context.Background())
-
-	return func(entry *logrus.Entry, hook *ElasticHook) error {
-		r := elastic.NewBulkIndexRequest().
-			Index(hook.index()).
-			Type("log").
-			Doc(*createMessage(entry, hook))
-		processor.Add(r)
-		return nil
-	}, err
-}
-
 // Levels Required for logrus hook implementation
 func (hook *ElasticHook) Levels() []logrus.Level {
 	return hook.levels
@@ -220,3 +241,29 @@ func (hook *ElasticHook) Levels() []logrus.Level {
 func (hook *ElasticHook) Cancel() {
 	hook.ctxCancel()
 }
+
+// Flush index the buffered entries, no-op for the synchronous hooks
+func (hook *ElasticHook) Flush(ctx context.Context) error {
+	if hook.buffer == nil {
+		return nil
+	}
+	return hook.buffer.flush(ctx)
+}
+
+// Close flush the buffered entries until ctx is done, the remaining ones are spooled,
+// then cancel the calls to elastic. Entries fired after Close are dropped
+func (hook *ElasticHook) Close(ctx context.Context) error {
+	defer hook.ctxCancel()
+	if hook.buffer == nil {
+		return nil
+	}
+	return hook.buffer.close(ctx)
+}
+
+// Stats counters of the buffered hooks, zero for the synchronous ones
+func (hook *ElasticHook) Stats() BufferStats {
+	if hook.buffer == nil {
+		return BufferStats{}
+	}
+	return hook.buffer.stats()
+}
@@ -0,0 +1,151 @@
+package pllog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/olivere/elastic/v7"
+)
+
+// IndexLifecycle write alias of the log indices with their template and ILM policy.
+// Entries are indexed through the alias, Elasticsearch rolls the backing index <alias>-000001,
+// <alias>-000002... over by size or age and deletes them after the retention:
+//
+//	lifecycle := pllog.IndexLifecycle{Alias: "orders-logs", RolloverMaxSize: "50gb", RolloverMaxAge: "1d", DeleteAfter: "30d"}
+//	if err := lifecycle.Install(ctx, client); err != nil {
+//		return err
+//	}
+//	hook, err := pllog.NewBufferedElasticHookWithFunc(client, host, level, lifecycle.IndexName, pllog.BufferOptions{})
+//
+// Without rollover nor retention no policy is installed, the OSS distribution has no ILM
+type IndexLifecycle struct {
+	Alias           string
+	RolloverMaxSize string // e.g. 50gb
+	RolloverMaxAge  string // e.g. 1d
+	RolloverMaxDocs int64
+	DeleteAfter     string // age of the rolled over indices deleted, e.g. 30d, kept when empty
+	Shards          int    // 1 by default
+	Replicas        int
+}
+
+// IndexName IndexNameFunc of the hooks writing to the alias
+func (l IndexLifecycle) IndexName() string {
+	return l.Alias
+}
+
+// PolicyName name of the ILM policy
+func (l IndexLifecycle) PolicyName() string {
+	return l.Alias + "-policy"
+}
+
+// HasPolicy true when the lifecycle rolls over or deletes the indices
+func (l IndexLifecycle) HasPolicy() bool {
+	return l.RolloverMaxSize != "" || l.RolloverMaxAge != "" || l.RolloverMaxDocs > 0 || l.DeleteAfter != ""
+}
+
+// Policy body of the ILM policy
+func (l IndexLifecycle) Policy() map[string]interface{} {
+	phases := map[string]interface{}{}
+	rollover := map[string]interface{}{}
+	if l.RolloverMaxSize != "" {
+		rollover["max_size"] = l.RolloverMaxSize
+	}
+	if l.RolloverMaxAge != "" {
+		rollover["max_age"] = l.RolloverMaxAge
+	}
+	if l.RolloverMaxDocs > 0 {
+		rollover["max_docs"] = l.RolloverMaxDocs
+	}
+	if len(rollover) > 0 {
+		phases["hot"] = map[string]interface{}{
+			"actions": map[string]interface{}{"rollover": rollover},
+		}
+	}
+	if l.DeleteAfter != "" {
+		phases["delete"] = map[string]interface{}{
+			"min_age": l.DeleteAfter,
+			"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+		}
+	}
+	return map[string]interface{}{"policy": map[string]interface{}{"phases": phases}}
+}
+
+// Template body of the index template of the backing indices, mappings of the hook messages
+func (l IndexLifecycle) Template() map[string]interface{} {
+	shards := l.Shards
+	if shards < 1 {
+		shards = 1
+	}
+	settings := map[string]interface{}{
+		"number_of_shards":   shards,
+		"number_of_replicas": l.Replicas,
+	}
+	if l.HasPolicy() {
+		settings["index.lifecycle.name"] = l.PolicyName()
+		settings["index.lifecycle.rollover_alias"] = l.Alias
+	}
+	return map[string]interface{}{
+		"index_patterns": []string{l.Alias + "-*"},
+		"settings":       settings,
+		"mappings":       MessageMappings(),
+	}
+}
+
+// MessageMappings explicit mappings of the hook messages, the string fields are keywords
+// so the request and correlation ids can be filtered and aggregated
+func MessageMappings() map[string]interface{} {
+	keyword := map[string]interface{}{"type": "keyword", "ignore_above": 1024}
+	return map[string]interface{}{
+		"dynamic_templates": []interface{}{
+			map[string]interface{}{
+				"fields_strings": map[string]interface{}{
+					"path_match":         "fields.*",
+					"match_mapping_type": "string",
+					"mapping":            keyword,
+				},
+			},
+		},
+		"properties": map[string]interface{}{
+			"host":       map[string]interface{}{"type": "keyword"},
+			"@timestamp": map[string]interface{}{"type": "date"},
+			"level":      map[string]interface{}{"type": "keyword"},
+			"message": map[string]interface{}{
+				"type":   "text",
+				"fields": map[string]interface{}{"keyword": keyword},
+			},
+			"fields": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{ErrorKey: map[string]interface{}{"type": "text"}},
+			},
+		},
+	}
+}
+
+// Install put the ILM policy and the index template then create the first backing index
+// with the write alias unless the alias exists, safe to call by every instance at startup
+func (l IndexLifecycle) Install(ctx context.Context, client *elastic.Client) error {
+	if l.Alias == "" {
+		return fmt.Errorf("index lifecycle without alias")
+	}
+	if l.HasPolicy() {
+		if _, err := client.XPackIlmPutLifecycle().Policy(l.PolicyName()).BodyJson(l.Policy()).Do(ctx); err != nil {
+			return fmt.Errorf("installing the log index policy failed: %v", err)
+		}
+	}
+	if _, err := client.IndexPutTemplate(l.Alias).BodyJson(l.Template()).Do(ctx); err != nil {
+		return fmt.Errorf("installing the log index template failed: %v", err)
+	}
+	exists, err := client.IndexExists(l.Alias).Do(ctx)
+	if err != nil || exists {
+		return err
+	}
+	_, err = client.CreateIndex(l.Alias + "-000001").BodyJson(map[string]interface{}{
+		"aliases": map[string]interface{}{
+			l.Alias: map[string]interface{}{"is_write_index": true},
+		},
+	}).Do(ctx)
+	if e, ok := err.(*elastic.Error); ok && e.Details != nil && e.Details.Type == "resource_already_exists_exception" {
+		return nil // created by another instance
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package pllog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/olivere/elastic/v7"
+)
+
+func TestIndexLifecycleInstall(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	bodies := map[string]map[string]interface{}{}
+	aliasExists := false
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := r.Method + " " + r.URL.Path
+		requests = append(requests, key)
+		if b, _ := io.ReadAll(r.Body); len(b) > 0 {
+			var body map[string]interface{}
+			json.Unmarshal(b, &body)
+			bodies[key] = body
+		}
+		if r.Method == http.MethodHead {
+			if !aliasExists {
+				rw.WriteHeader(http.StatusNotFound)
+			}
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write([]byte(`{"acknowledged":true}`))
+	}))
+	defer ts.Close()
+	client, err := elastic.NewClient(elastic.SetURL(ts.URL), elastic.SetSniff(false), elastic.SetHealthcheck(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lifecycle := IndexLifecycle{Alias: "core-logs", RolloverMaxSize: "50gb", RolloverMaxAge: "1d", DeleteAfter: "30d"}
+	if err := lifecycle.Install(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"PUT /_ilm/policy/core-logs-policy",
+		"PUT /_template/core-logs",
+		"HEAD /core-logs",
+		"PUT /core-logs-000001",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected %v but got %v", expected, requests)
+	}
+
+	template := bodies["PUT /_template/core-logs"]
+	settings := template["settings"].(map[string]interface{})
+	if settings["index.lifecycle.rollover_alias"] != "core-logs" || settings["index.lifecycle.name"] != "core-logs-policy" {
+		t.Errorf("expected the lifecycle settings but got %v", settings)
+	}
+	properties := template["mappings"].(map[string]interface{})["properties"].(map[string]interface{})
+	if properties["@timestamp"].(map[string]interface{})["type"] != "date" || properties["level"].(map[string]interface{})["type"] != "keyword" {
+		t.Errorf("expected explicit mappings but got %v", properties)
+	}
+	policy := bodies["PUT /_ilm/policy/core-logs-policy"]["policy"].(map[string]interface{})["phases"].(map[string]interface{})
+	if policy["delete"].(map[string]interface{})["min_age"] != "30d" {
+		t.Errorf("expected the delete phase but got %v", policy)
+	}
+	aliases := bodies["PUT /core-logs-000001"]["aliases"].(map[string]interface{})
+	if aliases["core-logs"].(map[string]interface{})["is_write_index"] != true {
+		t.Errorf("expected the write alias but got %v", aliases)
+	}
+
+	// the next instances keep the existing alias
+	requests = nil
+	aliasExists = true
+	if err := (IndexLifecycle{Alias: "core-logs"}).Install(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"PUT /_template/core-logs", "HEAD /core-logs"}; !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected %v without policy nor index but got %v", expected, requests)
+	}
+}
@@ -0,0 +1,9 @@
+package pllog
+
+import "os"
+
+// SetExit replace the exit of Fatal for the pllog_test tests, returns the function restoring it
+func SetExit(f func(code int)) (restore func()) {
+	exit = f
+	return func() { exit = os.Exit }
+}
@@ -0,0 +1,193 @@
+package pllog
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileOptions rotation of the log file, zero values disable the corresponding rotation
+type FileOptions struct {
+	Path        string
+	MaxSize     int64         // bytes written before the file is rotated
+	RotateEvery time.Duration // age of the file rotated, e.g. 24h
+	MaxBackups  int           // rotated files kept, all of them when zero
+	Compress    bool          // gzip the rotated files
+	// OnError called with the rotation failures, they are printed by the log package by default.
+	// It must not log to the hooked logger
+	OnError func(err error)
+}
+
+// FileSink sink writing the messages as JSON lines to a rotating file:
+// app.log is renamed app.log.2006-01-02T15-04-05.000 (.gz when compressed) on rotation
+type FileSink struct {
+	opts     FileOptions
+	mu       sync.Mutex
+	file     *os.File // nil after a failed rotation until reopened
+	closed   bool
+	size     int64
+	openedAt time.Time
+	wg       sync.WaitGroup
+	bgMu     sync.Mutex // one compression and cleanup at a time
+	now      func() time.Time
+}
+
+// NewFileSink sink of the file, appended when it exists
+func NewFileSink(opts FileOptions) (*FileSink, error) {
+	if opts.OnError == nil {
+		opts.OnError = func(err error) {
+			log.Printf("pllog: %v", err)
+		}
+	}
+	s := &FileSink{opts: opts, now: time.Now}
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSink) Send(_ context.Context, messages []*Message) error {
+	var b strings.Builder
+	for _, m := range messages {
+		line, err := json.Marshal(m)
+		if err != nil {
+			return &PermanentError{err}
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return os.ErrClosed
+	}
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	if s.shouldRotate(int64(b.Len())) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := io.WriteString(s.file, b.String())
+	s.size += int64(n)
+	return err
+}
+
+// Close close the file after the rotated files are compressed
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	var err error
+	if s.file != nil {
+		err = s.file.Close()
+		s.file = nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.file, s.size, s.openedAt = f, fi.Size(), s.now()
+	return nil
+}
+
+func (s *FileSink) shouldRotate(n int64) bool {
+	if s.opts.MaxSize > 0 && s.size > 0 && s.size+n > s.opts.MaxSize {
+		return true
+	}
+	return s.opts.RotateEvery > 0 && s.now().Sub(s.openedAt) >= s.opts.RotateEvery
+}
+
+// rotate rename the current file and open a new one, compression and cleanup run in background.
+// When the rename fails the error is reported to OnError and the writing goes on in the current file,
+// the next rotation is attempted after MaxSize more bytes or RotateEvery
+func (s *FileSink) rotate() error {
+	err := s.file.Close()
+	s.file = nil
+	if err != nil {
+		return err
+	}
+	rotated := s.opts.Path + "." + s.now().Format("2006-01-02T15-04-05.000")
+	renameErr := os.Rename(s.opts.Path, rotated)
+	if err := s.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		s.opts.OnError(fmt.Errorf("rotating log file failed: %v", renameErr))
+		s.size = 0 // open reset openedAt
+		return nil
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.bgMu.Lock()
+		defer s.bgMu.Unlock()
+		if s.opts.Compress {
+			compressFile(rotated)
+		}
+		s.removeBackups()
+	}()
+	return nil
+}
+
+// removeBackups remove the oldest rotated files beyond MaxBackups
+func (s *FileSink) removeBackups() {
+	if s.opts.MaxBackups < 1 {
+		return
+	}
+	backups, _ := filepath.Glob(s.opts.Path + ".*")
+	sort.Strings(backups)
+	for len(backups) > s.opts.MaxBackups {
+		os.Remove(backups[0])
+		backups = backups[1:]
+	}
+}
+
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err == nil {
+		err = zw.Close()
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(name + ".gz")
+		return err
+	}
+	return os.Remove(name)
+}
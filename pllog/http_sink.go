@@ -0,0 +1,131 @@
+package pllog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HTTPFormatJSON messages posted as a JSON array, for the Logstash http input with the json codec
+	HTTPFormatJSON = "json"
+	// HTTPFormatLoki messages pushed to the Loki push API (/loki/api/v1/push), one stream by level
+	HTTPFormatLoki = "loki"
+)
+
+// HTTPOptions collector of the HTTP sink
+type HTTPOptions struct {
+	URL    string
+	Format string // HTTPFormatJSON by default
+	Header http.Header
+	Labels map[string]string // Loki stream labels besides host and level
+	Client *http.Client      // 10s timeout by default
+}
+
+// HTTPSink sink posting the batches of messages to a log collector. 4xx answers except 429 are permanent
+type HTTPSink struct {
+	opts HTTPOptions
+}
+
+// NewHTTPSink sink of the options
+func NewHTTPSink(opts HTTPOptions) (*HTTPSink, error) {
+	switch opts.Format {
+	case "":
+		opts.Format = HTTPFormatJSON
+	case HTTPFormatJSON, HTTPFormatLoki:
+	default:
+		return nil, fmt.Errorf("not a valid http log format: %q", opts.Format)
+	}
+	if opts.URL == "" {
+		return nil, fmt.Errorf("http log sink without url")
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPSink{opts: opts}, nil
+}
+
+func (s *HTTPSink) Send(ctx context.Context, messages []*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	var body interface{} = messages
+	if s.opts.Format == HTTPFormatLoki {
+		body = s.lokiPush(messages)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return &PermanentError{err}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.opts.URL, bytes.NewReader(payload))
+	if err != nil {
+		return &PermanentError{err}
+	}
+	for k, v := range s.opts.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("log collector answered %s", resp.Status)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return &PermanentError{err}
+	}
+	return err
+}
+
+func (s *HTTPSink) Close() error {
+	return nil
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiPush push request of the messages, the lines are the JSON messages without host and level labels
+func (s *HTTPSink) lokiPush(messages []*Message) map[string][]*lokiStream {
+	streams := map[string]*lokiStream{}
+	for _, m := range messages {
+		key := m.Host + "\x00" + m.Level
+		stream, ok := streams[key]
+		if !ok {
+			labels := map[string]string{"host": m.Host, "level": strings.ToLower(m.Level)}
+			for k, v := range s.opts.Labels {
+				labels[k] = v
+			}
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+		}
+		ts := time.Now()
+		if t, err := time.Parse(time.RFC3339Nano, m.Timestamp); err == nil {
+			ts = t
+		}
+		line, _ := json.Marshal(map[string]interface{}{"message": m.Message, "fields": m.Fields})
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(ts.UnixNano(), 10), string(line)})
+	}
+	keys := make([]string, 0, len(streams))
+	for k := range streams {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	push := make([]*lokiStream, len(keys))
+	for i, k := range keys {
+		push[i] = streams[k]
+	}
+	return map[string][]*lokiStream{"streams": push}
+}
@@ -0,0 +1,222 @@
+package pllog
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ModuleKey field of the module name of the entries of Module, its level applies to them
+const ModuleKey = "module"
+
+// LevelController runtime log levels, the global one and overrides by module. Modules are dotted names,
+// orders.repository takes the level of orders when it has none. Changes can revert after a timeout
+// so a service flipped to debug during an incident goes back to its configured level
+type LevelController struct {
+	mu        sync.RWMutex
+	base      logrus.Level
+	global    logrus.Level
+	modules   map[string]logrus.Level
+	timers    map[string]*time.Timer // by module, "" for the global level
+	revertAt  map[string]time.Time
+	listeners []func()
+}
+
+// LevelsSnapshot levels of a controller, revert times by module, "" for the global level
+type LevelsSnapshot struct {
+	Base     string               `json:"base"`
+	Global   string               `json:"global"`
+	Modules  map[string]string    `json:"modules"`
+	RevertAt map[string]time.Time `json:"revert_at,omitempty"`
+}
+
+// NewLevelController controller with the configured level, Reset goes back to it
+func NewLevelController(level logrus.Level) *LevelController {
+	return &LevelController{
+		base:     level,
+		global:   level,
+		modules:  map[string]logrus.Level{},
+		timers:   map[string]*time.Timer{},
+		revertAt: map[string]time.Time{},
+	}
+}
+
+// Level global level
+func (c *LevelController) Level() logrus.Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.global
+}
+
+// ModuleLevel level of the module, the one of its closest parent or the global level without override
+func (c *LevelController) ModuleLevel(module string) logrus.Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for module != "" {
+		if l, ok := c.modules[module]; ok {
+			return l
+		}
+		i := strings.LastIndexByte(module, '.')
+		if i < 0 {
+			break
+		}
+		module = module[:i]
+	}
+	return c.global
+}
+
+// Enabled true when the entries of the module at the level are logged
+func (c *LevelController) Enabled(module string, level logrus.Level) bool {
+	return c.ModuleLevel(module) >= level
+}
+
+// MaxLevel most verbose level of the global level and the modules, loggers filtering
+// before the module is known must let it through
+func (c *LevelController) MaxLevel() logrus.Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	max := c.global
+	for _, l := range c.modules {
+		if l > max {
+			max = l
+		}
+	}
+	return max
+}
+
+// SetLevel change the global level, reverted to the configured one after revertAfter when not zero
+func (c *LevelController) SetLevel(level logrus.Level, revertAfter time.Duration) {
+	c.set("", level, revertAfter)
+}
+
+// SetModuleLevel override the level of the module, removed after revertAfter when not zero
+func (c *LevelController) SetModuleLevel(module string, level logrus.Level, revertAfter time.Duration) {
+	c.set(module, level, revertAfter)
+}
+
+// Reset global level back to the configured one and module overrides removed
+func (c *LevelController) Reset() {
+	c.mu.Lock()
+	for key, t := range c.timers {
+		t.Stop()
+		delete(c.timers, key)
+		delete(c.revertAt, key)
+	}
+	c.global = c.base
+	c.modules = map[string]logrus.Level{}
+	c.mu.Unlock()
+	c.notify()
+}
+
+// ResetModule remove the override of the module, "" resets the global level only
+func (c *LevelController) ResetModule(module string) {
+	c.mu.Lock()
+	c.reset(module)
+	c.mu.Unlock()
+	c.notify()
+}
+
+// OnChange call f after every change, reverts included
+func (c *LevelController) OnChange(f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, f)
+}
+
+// Snapshot current levels
+func (c *LevelController) Snapshot() LevelsSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s := LevelsSnapshot{
+		Base:     c.base.String(),
+		Global:   c.global.String(),
+		Modules:  make(map[string]string, len(c.modules)),
+		RevertAt: make(map[string]time.Time, len(c.revertAt)),
+	}
+	for m, l := range c.modules {
+		s.Modules[m] = l.String()
+	}
+	for m, t := range c.revertAt {
+		s.RevertAt[m] = t
+	}
+	return s
+}
+
+func (c *LevelController) set(module string, level logrus.Level, revertAfter time.Duration) {
+	c.mu.Lock()
+	if t, ok := c.timers[module]; ok {
+		t.Stop()
+		delete(c.timers, module)
+		delete(c.revertAt, module)
+	}
+	if module == "" {
+		c.global = level
+	} else {
+		c.modules[module] = level
+	}
+	if revertAfter > 0 {
+		var t *time.Timer
+		t = time.AfterFunc(revertAfter, func() {
+			c.mu.Lock()
+			if c.timers[module] != t {
+				c.mu.Unlock()
+				return // changed again meanwhile
+			}
+			c.reset(module)
+			c.mu.Unlock()
+			c.notify()
+		})
+		c.timers[module] = t
+		c.revertAt[module] = time.Now().Add(revertAfter)
+	}
+	c.mu.Unlock()
+	c.notify()
+}
+
+// reset the module or global level, called with the lock held
+func (c *LevelController) reset(module string) {
+	if t, ok := c.timers[module]; ok {
+		t.Stop()
+		delete(c.timers, module)
+		delete(c.revertAt, module)
+	}
+	if module == "" {
+		c.global = c.base
+	} else {
+		delete(c.modules, module)
+	}
+}
+
+func (c *LevelController) notify() {
+	c.mu.RLock()
+	listeners := append([]func(){}, c.listeners...)
+	c.mu.RUnlock()
+	for _, f := range listeners {
+		f()
+	}
+}
+
+// LevelControlled implemented by the loggers with runtime levels
+type LevelControlled interface {
+	LevelController() *LevelController
+	// Module entry of the module, filtered by the module level
+	Module(name string) PlLogentry
+}
+
+// LevelsOf level controller of the logger, nil when its levels are fixed
+func LevelsOf(log PlLogger) *LevelController {
+	if l, ok := log.(LevelControlled); ok {
+		return l.LevelController()
+	}
+	return nil
+}
+
+// Module entry of the module, a plain module field for the loggers without runtime levels
+func Module(log PlLogger, name string) PlLogentry {
+	if l, ok := log.(LevelControlled); ok {
+		return l.Module(name)
+	}
+	return log.WithField(ModuleKey, name)
+}
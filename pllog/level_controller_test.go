@@ -0,0 +1,95 @@
+package pllog
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestLevelController(t *testing.T) {
+	c := NewLevelController(logrus.InfoLevel)
+	var changes int32
+	c.OnChange(func() { atomic.AddInt32(&changes, 1) })
+
+	c.SetModuleLevel("orders", logrus.DebugLevel, 0)
+	c.SetModuleLevel("orders.repository", logrus.TraceLevel, 0)
+	tt := []struct {
+		module   string
+		expected logrus.Level
+	}{
+		{module: "", expected: logrus.InfoLevel},
+		{module: "users", expected: logrus.InfoLevel},
+		{module: "orders", expected: logrus.DebugLevel},
+		{module: "orders.handler", expected: logrus.DebugLevel},
+		{module: "orders.repository.sql", expected: logrus.TraceLevel},
+		{module: "ordersx", expected: logrus.InfoLevel},
+	}
+	for _, tc := range tt {
+		if l := c.ModuleLevel(tc.module); l != tc.expected {
+			t.Errorf("expected %s level %s but got %s", tc.module, tc.expected, l)
+		}
+	}
+	if c.MaxLevel() != logrus.TraceLevel {
+		t.Errorf("expected trace max level but got %s", c.MaxLevel())
+	}
+
+	c.SetLevel(logrus.DebugLevel, 20*time.Millisecond)
+	if c.Level() != logrus.DebugLevel || c.Snapshot().RevertAt[""].IsZero() {
+		t.Errorf("expected debug reverting but got %+v", c.Snapshot())
+	}
+	time.Sleep(60 * time.Millisecond)
+	if c.Level() != logrus.InfoLevel || len(c.Snapshot().RevertAt) != 0 {
+		t.Errorf("expected the level reverted to info but got %+v", c.Snapshot())
+	}
+
+	c.Reset()
+	if c.ModuleLevel("orders.repository") != logrus.InfoLevel || atomic.LoadInt32(&changes) != 5 {
+		t.Errorf("expected the modules reset after 5 changes but got %+v after %d", c.Snapshot(), changes)
+	}
+}
+
+func TestDefaultLoggerModuleLevels(t *testing.T) {
+	var buf bytes.Buffer
+	levels := NewLevelController(logrus.InfoLevel)
+	l := &DefaultLogger{Level: logrus.InfoLevel, Out: &buf, Levels: levels}
+
+	levels.SetModuleLevel("orders", logrus.DebugLevel, 0)
+	Module(l, "orders").Debug("order debug")
+	Module(l, "users").Debug("user debug")
+	l.Debug("global debug")
+
+	if out := buf.String(); !strings.Contains(out, "order debug module=orders") || strings.Contains(out, "user debug") || strings.Contains(out, "global debug") {
+		t.Errorf("expected the orders module debug only but got %q", out)
+	}
+	if LevelsOf(l) != levels || LevelsOf(NewDefaultLogger(logrus.InfoLevel)) != nil {
+		t.Error("expected the controller of the logger")
+	}
+}
+
+func TestLogrusLoggerModuleLevels(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewWithRef(&LogrusLogger{LogLevel: "info", LogFile: filepath.Join(t.TempDir(), "app.log"), Out: &buf}).(*LogrusLogger)
+	buf.Reset()
+
+	l.Debug("hidden")
+	LevelsOf(l).SetModuleLevel("orders", logrus.DebugLevel, 0)
+	Module(l, "orders").Debugf("order %d", 1)
+	Module(l, "users").Debug("user debug")
+	l.WithField("user", "jed").Debug("global debug")
+	LevelsOf(l).SetLevel(logrus.WarnLevel, 0)
+	l.Info("info hidden")
+	Module(l, "orders").Info("order info")
+
+	out := buf.String()
+	if !strings.Contains(out, "order 1") || !strings.Contains(out, "order info") || strings.Contains(out, "hidden") || strings.Contains(out, "user debug") || strings.Contains(out, "global debug") {
+		t.Errorf("expected the orders module entries only but got %q", out)
+	}
+	if l.Logger.GetLevel() != logrus.DebugLevel {
+		t.Errorf("expected logrus at the most verbose level but got %s", l.Logger.GetLevel())
+	}
+}
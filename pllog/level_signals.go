@@ -0,0 +1,42 @@
+//go:build !windows
+
+package pllog
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HandleLevelSignals SIGUSR1 switch the global level to debug for revertAfter, or back to the
+// configured level when already changed, SIGHUP reset the global and module levels. Returns the
+// function stopping the handling
+func HandleLevelSignals(levels *LevelController, revertAfter time.Duration) (stop func()) {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, syscall.SIGUSR1, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case sig := <-c:
+				switch {
+				case sig == syscall.SIGHUP:
+					levels.Reset()
+				case levels.Level() == levels.base:
+					levels.SetLevel(logrus.DebugLevel, revertAfter)
+				default:
+					levels.ResetModule("")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(c)
+		close(done)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build !windows
+
+package pllog
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestHandleLevelSignals(t *testing.T) {
+	levels := NewLevelController(logrus.InfoLevel)
+	stop := HandleLevelSignals(levels, time.Minute)
+	defer stop()
+
+	waitLevel := func(expected logrus.Level) {
+		t.Helper()
+		for i := 0; i < 100 && levels.Level() != expected; i++ {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if levels.Level() != expected {
+			t.Fatalf("expected %s but got %s", expected, levels.Level())
+		}
+	}
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitLevel(logrus.DebugLevel)
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	waitLevel(logrus.InfoLevel)
+
+	levels.SetModuleLevel("orders", logrus.TraceLevel, 0)
+	levels.SetLevel(logrus.ErrorLevel, 0)
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	waitLevel(logrus.InfoLevel)
+	if levels.ModuleLevel("orders") != logrus.InfoLevel {
+		t.Errorf("expected the module levels reset but got %+v", levels.Snapshot())
+	}
+}
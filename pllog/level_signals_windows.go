@@ -0,0 +1,8 @@
+package pllog
+
+import "time"
+
+// HandleLevelSignals no-op, Windows has no SIGUSR1 nor SIGHUP
+func HandleLevelSignals(levels *LevelController, revertAfter time.Duration) (stop func()) {
+	return func() {}
+}
@@ -1,6 +1,10 @@
 package pllog
 
-import "context"
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
 
 const (
 	RequestIDHeaderKey     = "Request-Id"
@@ -9,9 +13,37 @@ const (
 	CorrelationID          = "CorrelationId"
 )
 
+// ErrorKey field of the error attached by WithError
+var ErrorKey = logrus.ErrorKey
+
+// Closer implemented by the loggers buffering entries, servers call Close on shutdown
+type Closer interface {
+	Flush(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// Close close the logger when it buffers entries
+func Close(ctx context.Context, log PlLogger) error {
+	if c, ok := log.(Closer); ok {
+		return c.Close(ctx)
+	}
+	return nil
+}
+
 func CreateLogEntryFromContext(ctx context.Context, log PlLogger) PlLogentry {
-	return log.WithFields(map[string]interface{}{
-		CorrelationID: ctx.Value(CorrelationID),
-		RequestID:     ctx.Value(RequestID),
-	})
+	return log.WithContext(ctx)
+}
+
+// contextFields request and correlation ids of the context
+func contextFields(ctx context.Context) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if ctx == nil {
+		return fields
+	}
+	for _, key := range []string{CorrelationID, RequestID} {
+		if v := ctx.Value(key); v != nil {
+			fields[key] = v
+		}
+	}
+	return fields
 }
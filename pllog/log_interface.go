@@ -1,5 +1,7 @@
 package pllog
 
+import "context"
+
 type PlLogger interface {
 	Trace(args ...interface{})
 	Debug(args ...interface{})
@@ -16,6 +18,9 @@ type PlLogger interface {
 	Fatalf(format string, args ...interface{})
 	Panicf(format string, args ...interface{})
 	WithFields(map[string]interface{}) PlLogentry
+	WithField(key string, value interface{}) PlLogentry
+	WithError(err error) PlLogentry
+	WithContext(ctx context.Context) PlLogentry
 }
 
 type PlLogentry interface {
@@ -33,4 +38,8 @@ type PlLogentry interface {
 	Errorf(format string, args ...interface{})
 	Fatalf(format string, args ...interface{})
 	Panicf(format string, args ...interface{})
+	WithFields(map[string]interface{}) PlLogentry
+	WithField(key string, value interface{}) PlLogentry
+	WithError(err error) PlLogentry
+	WithContext(ctx context.Context) PlLogentry
 }
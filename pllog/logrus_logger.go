@@ -1,24 +1,53 @@
 package pllog
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/jedrp/go-core/redact"
 	"github.com/jessevdk/go-flags"
 	"github.com/olivere/elastic/v7"
 	"github.com/sirupsen/logrus"
 )
 
 type LogrusLogger struct {
-	ElasticHostURL string `long:"log-host-url" description:"the url of elastichsearch database url" env:"LOG_HOST_URL"`
-	Sniff          bool   `long:"log-enable-sniff" description:"Enable or disable sniff" env:"LOG_SNIFF"`
-	LogIndexPrefix string `long:"log-prefix" description:"the prefix of index name" env:"LOG_INDEX_PREFIX"`
-	LogHostName    string `long:"log-host-name" description:"the prefix of index name" env:"LOG_HOST_NAME"`
-	Enable         bool   `long:"log-enable" description:"the prefix of index name" env:"LOG_ENABLE"`
-	LogLevel       string `long:"log-level" description:"the prefix of index name" env:"LOG_LEVEL"`
-	IndexNameFunc  func() string
+	ElasticHostURL    string        `long:"log-host-url" description:"the url of elastichsearch database url" env:"LOG_HOST_URL"`
+	Sniff             bool          `long:"log-enable-sniff" description:"Enable or disable sniff" env:"LOG_SNIFF"`
+	LogIndexPrefix    string        `long:"log-prefix" description:"the prefix of index name" env:"LOG_INDEX_PREFIX"`
+	LogHostName       string        `long:"log-host-name" description:"the prefix of index name" env:"LOG_HOST_NAME"`
+	Enable            bool          `long:"log-enable" description:"the prefix of index name" env:"LOG_ENABLE"`
+	LogLevel          string        `long:"log-level" description:"the prefix of index name" env:"LOG_LEVEL"`
+	LogFormat         string        `long:"log-format" description:"console log format, text or json" env:"LOG_FORMAT" default:"text"`
+	LogBufferSize     int           `long:"log-buffer-size" description:"entries buffered before indexing in elasticsearch" env:"LOG_BUFFER_SIZE" default:"10000"`
+	LogDropPolicy     string        `long:"log-drop-policy" description:"when the log buffer is full: drop-newest, drop-oldest or block" env:"LOG_DROP_POLICY" default:"drop-newest"`
+	LogSpoolDir       string        `long:"log-spool-dir" description:"directory keeping the entries while elasticsearch is down" env:"LOG_SPOOL_DIR"`
+	LogILM            bool          `long:"log-ilm" description:"index through the write alias log-prefix with rollover and retention instead of daily indices" env:"LOG_ILM"`
+	LogRolloverSize   string        `long:"log-rollover-size" description:"size of the log index rolled over" env:"LOG_ROLLOVER_SIZE" default:"50gb"`
+	LogRolloverAge    string        `long:"log-rollover-age" description:"age of the log index rolled over" env:"LOG_ROLLOVER_AGE" default:"1d"`
+	LogRetention      string        `long:"log-retention" description:"age of the rolled over log indices deleted, kept when empty" env:"LOG_RETENTION" default:"30d"`
+	LogFile           string        `long:"log-file" description:"path of the rotating log file" env:"LOG_FILE"`
+	LogFileLevel      string        `long:"log-file-level" description:"level of the log file, log-level when empty" env:"LOG_FILE_LEVEL"`
+	LogFileMaxSize    int64         `long:"log-file-max-size" description:"size in MB of the log file rotated" env:"LOG_FILE_MAX_SIZE" default:"100"`
+	LogFileRotate     time.Duration `long:"log-file-rotate" description:"age of the log file rotated" env:"LOG_FILE_ROTATE" default:"24h"`
+	LogFileMaxBackups int           `long:"log-file-max-backups" description:"rotated log files kept" env:"LOG_FILE_MAX_BACKUPS" default:"7"`
+	LogFileCompress   bool          `long:"log-file-compress" description:"gzip the rotated log files" env:"LOG_FILE_COMPRESS"`
+	LogSyslog         string        `long:"log-syslog" description:"syslog address, e.g. udp://localhost:514 or unixgram:///dev/log" env:"LOG_SYSLOG"`
+	LogSyslogLevel    string        `long:"log-syslog-level" description:"level of syslog, log-level when empty" env:"LOG_SYSLOG_LEVEL"`
+	LogSyslogApp      string        `long:"log-syslog-app" description:"app name of the syslog messages" env:"LOG_SYSLOG_APP"`
+	LogHTTPURL        string        `long:"log-http-url" description:"url of the log collector the entries are posted to" env:"LOG_HTTP_URL"`
+	LogHTTPFormat     string        `long:"log-http-format" description:"format of the log collector, json or loki" env:"LOG_HTTP_FORMAT" default:"json"`
+	LogHTTPLevel      string        `long:"log-http-level" description:"level of the log collector, log-level when empty" env:"LOG_HTTP_LEVEL"`
+	LogLevelSignals   bool          `long:"log-level-signals" description:"SIGUSR1 toggles the debug level, SIGHUP resets the levels" env:"LOG_LEVEL_SIGNALS"`
+	LogLevelRevert    time.Duration `long:"log-level-revert" description:"time after which a level changed by signal reverts" env:"LOG_LEVEL_REVERT" default:"15m"`
+	Out               io.Writer
+	IndexNameFunc     func() string
+	hooks             []Closer
+	levels            *LevelController
 	*logrus.Logger
 }
 
@@ -27,10 +56,7 @@ func New() PlLogger {
 		LogLevel: "debug",
 	}
 
-	logrusLogger.IndexNameFunc = func() string {
-		dt := time.Now()
-		return fmt.Sprintf("%s-%s", logrusLogger.LogIndexPrefix, dt.Format("2006-01-02"))
-	}
+	logrusLogger.IndexNameFunc = logrusLogger.dailyIndexName
 	parser := flags.NewParser(logrusLogger, flags.IgnoreUnknown)
 	if _, err := parser.Parse(); err != nil {
 		code := 1
@@ -50,32 +76,355 @@ func NewWithRef(logrusLogger *LogrusLogger) PlLogger {
 	if err != nil {
 		log.Panic(err)
 	}
-
-	if !logrusLogger.Enable {
-		return NewDefaultLogger(level)
+	if err := ValidateLogFormat(logrusLogger.LogFormat); err != nil {
+		log.Panic(err)
 	}
-
-	log := logrus.New()
-	log.Level = level
-
-	client, err := elastic.NewClient(elastic.SetSniff(false), elastic.SetURL(logrusLogger.ElasticHostURL))
+	dropPolicy, err := ParseDropPolicy(logrusLogger.LogDropPolicy)
 	if err != nil {
 		log.Panic(err)
 	}
+	bufferOptions := BufferOptions{
+		Size:       logrusLogger.LogBufferSize,
+		DropPolicy: dropPolicy,
+		SpoolDir:   logrusLogger.LogSpoolDir,
+	}
 
-	hook, err := NewElasticHookWithFunc(client, logrusLogger.LogHostName, level, logrusLogger.IndexNameFunc)
-	if err != nil {
-		log.Panic(err)
+	levels := NewLevelController(level)
+	if logrusLogger.LogLevelSignals {
+		HandleLevelSignals(levels, logrusLogger.LogLevelRevert)
 	}
-	log.Hooks.Add(hook)
+
+	if !logrusLogger.Enable && !logrusLogger.hasSinks() {
+		return &DefaultLogger{Level: level, Format: logrusLogger.LogFormat, Out: logrusLogger.Out, Levels: levels}
+	}
+
+	log := logrus.New()
+	// the controller filters by module, logrus must let the most verbose level through
+	log.SetLevel(levels.MaxLevel())
+	levels.OnChange(func() {
+		log.SetLevel(levels.MaxLevel())
+	})
+	logrusLogger.levels = levels
+	if logrusLogger.Out != nil {
+		log.Out = logrusLogger.Out
+	}
+	if logrusLogger.LogFormat == JSONFormat {
+		log.Formatter = &logrus.JSONFormatter{}
+	}
+	// first hook so the other hooks get the redacted message
+	log.Hooks.Add(&RedactHook{})
 
 	logrusLogger.Logger = log
+
+	// the entries are filtered by the level controller before the hooks, they take all levels
+	// unless they have their own
+	if logrusLogger.Enable {
+		if hook := logrusLogger.elasticHook(logrus.TraceLevel, bufferOptions); hook != nil {
+			log.Hooks.Add(hook)
+			logrusLogger.hooks = append(logrusLogger.hooks, hook)
+		}
+	}
+	for _, hook := range logrusLogger.sinkHooks(logrus.TraceLevel, bufferOptions) {
+		log.Hooks.Add(hook)
+		logrusLogger.hooks = append(logrusLogger.hooks, hook)
+	}
+	// Fatal exits the process, ship the buffered entries first
+	log.ExitFunc = func(code int) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		logrusLogger.Close(ctx)
+		os.Exit(code)
+	}
+
 	log.Printf("%+v\n", logrusLogger)
 	return logrusLogger
 }
 
+// elasticHook buffered hook of elasticsearch, nil when the client can't be created. The client skips the
+// startup health check: elasticsearch being down must not prevent the service from starting, the entries
+// are retried and spooled until it is back as for the outages at runtime
+func (logrusLogger *LogrusLogger) elasticHook(level logrus.Level, opts BufferOptions) *ElasticHook {
+	log := logrusLogger.Logger
+	client, err := elastic.NewClient(elastic.SetSniff(false), elastic.SetHealthcheck(false), elastic.SetURL(logrusLogger.ElasticHostURL))
+	if err != nil {
+		log.Warnf("elasticsearch logging disabled, no client of %s: %v", logrusLogger.ElasticHostURL, err)
+		return nil
+	}
+	indexFunc := logrusLogger.IndexNameFunc
+	if indexFunc == nil {
+		indexFunc = logrusLogger.dailyIndexName
+	}
+	if logrusLogger.LogILM {
+		lifecycle := IndexLifecycle{
+			Alias:           logrusLogger.LogIndexPrefix,
+			RolloverMaxSize: logrusLogger.LogRolloverSize,
+			RolloverMaxAge:  logrusLogger.LogRolloverAge,
+			DeleteAfter:     logrusLogger.LogRetention,
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := lifecycle.Install(ctx, client)
+		cancel()
+		if err != nil {
+			log.Warnf("log index lifecycle not installed, indexing without alias: %v", err)
+		} else {
+			indexFunc = lifecycle.IndexName
+		}
+	}
+	hook, err := NewBufferedElasticHookWithFunc(client, logrusLogger.LogHostName, level, indexFunc, opts)
+	if err != nil {
+		log.Warnf("elasticsearch logging disabled: %v", err)
+		return nil
+	}
+	return hook
+}
+
+// dailyIndexName index of the day of the index prefix, the default IndexNameFunc
+func (logrusLogger *LogrusLogger) dailyIndexName() string {
+	return fmt.Sprintf("%s-%s", logrusLogger.LogIndexPrefix, time.Now().Format("2006-01-02"))
+}
+
+func (logrusLogger *LogrusLogger) hasSinks() bool {
+	return logrusLogger.LogFile != "" || logrusLogger.LogSyslog != "" || logrusLogger.LogHTTPURL != ""
+}
+
+// sinkHooks buffered hooks of the configured file, syslog and HTTP sinks, each spooling in its own
+// directory of the spool. Invalid configurations panic as the levels do
+func (logrusLogger *LogrusLogger) sinkHooks(level logrus.Level, opts BufferOptions) []*SinkHook {
+	sinkLevel := func(name string) logrus.Level {
+		if name == "" {
+			return level
+		}
+		l, err := logrus.ParseLevel(name)
+		if err != nil {
+			log.Panic(err)
+		}
+		return l
+	}
+	var hooks []*SinkHook
+	add := func(name string, sink Sink, err error, level logrus.Level) {
+		if err != nil {
+			log.Panic(err)
+		}
+		sinkOpts := opts
+		if opts.SpoolDir != "" {
+			sinkOpts.SpoolDir = filepath.Join(opts.SpoolDir, name)
+		}
+		hook, err := NewBufferedSinkHook(sink, logrusLogger.LogHostName, level, sinkOpts)
+		if err != nil {
+			log.Panic(err)
+		}
+		hooks = append(hooks, hook)
+	}
+	if logrusLogger.LogFile != "" {
+		sink, err := NewFileSink(FileOptions{
+			Path:        logrusLogger.LogFile,
+			MaxSize:     logrusLogger.LogFileMaxSize << 20,
+			RotateEvery: logrusLogger.LogFileRotate,
+			MaxBackups:  logrusLogger.LogFileMaxBackups,
+			Compress:    logrusLogger.LogFileCompress,
+			OnError:     opts.OnError,
+		})
+		add("file", sink, err, sinkLevel(logrusLogger.LogFileLevel))
+	}
+	if logrusLogger.LogSyslog != "" {
+		syslogOpts, err := ParseSyslogAddress(logrusLogger.LogSyslog)
+		var sink *SyslogSink
+		if err == nil {
+			syslogOpts.AppName = logrusLogger.LogSyslogApp
+			sink, err = NewSyslogSink(syslogOpts)
+		}
+		add("syslog", sink, err, sinkLevel(logrusLogger.LogSyslogLevel))
+	}
+	if logrusLogger.LogHTTPURL != "" {
+		sink, err := NewHTTPSink(HTTPOptions{URL: logrusLogger.LogHTTPURL, Format: logrusLogger.LogHTTPFormat})
+		add("http", sink, err, sinkLevel(logrusLogger.LogHTTPLevel))
+	}
+	return hooks
+}
+
+// Flush ship the entries buffered by the elasticsearch and sink hooks
+func (logrusLogger *LogrusLogger) Flush(ctx context.Context) error {
+	var err error
+	for _, hook := range logrusLogger.hooks {
+		if e := hook.Flush(ctx); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Close flush the entries buffered by the hooks until ctx is done and stop shipping,
+// the console logging keeps working
+func (logrusLogger *LogrusLogger) Close(ctx context.Context) error {
+	var err error
+	for _, hook := range logrusLogger.hooks {
+		if e := hook.Close(ctx); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
 func (logrusLogger *LogrusLogger) WithFields(fields map[string]interface{}) PlLogentry {
-	return logrusLogger.Logger.WithFields(fields)
+	return logrusLogger.entry().WithFields(fields)
+}
+
+func (logrusLogger *LogrusLogger) WithField(key string, value interface{}) PlLogentry {
+	return logrusLogger.entry().WithField(key, value)
+}
+
+func (logrusLogger *LogrusLogger) WithError(err error) PlLogentry {
+	return logrusLogger.entry().WithError(err)
+}
+
+// WithContext entry with the request and correlation ids of the context
+func (logrusLogger *LogrusLogger) WithContext(ctx context.Context) PlLogentry {
+	return logrusLogger.entry().WithContext(ctx)
+}
+
+// LevelController runtime levels of the logger, nil when not created by NewWithRef
+func (logrusLogger *LogrusLogger) LevelController() *LevelController {
+	return logrusLogger.levels
+}
+
+// Module entry of the module, filtered by the module level
+func (logrusLogger *LogrusLogger) Module(name string) PlLogentry {
+	return logrusLogger.entry().WithField(ModuleKey, name)
+}
+
+func (logrusLogger *LogrusLogger) Trace(args ...interface{}) {
+	logrusLogger.entry().Trace(args...)
+}
+
+func (logrusLogger *LogrusLogger) Debug(args ...interface{}) {
+	logrusLogger.entry().Debug(args...)
+}
+
+func (logrusLogger *LogrusLogger) Info(args ...interface{}) {
+	logrusLogger.entry().Info(args...)
+}
+
+func (logrusLogger *LogrusLogger) Warn(args ...interface{}) {
+	logrusLogger.entry().Warn(args...)
+}
+
+func (logrusLogger *LogrusLogger) Error(args ...interface{}) {
+	logrusLogger.entry().Error(args...)
+}
+
+func (logrusLogger *LogrusLogger) Tracef(format string, args ...interface{}) {
+	logrusLogger.entry().Tracef(format, args...)
+}
+
+func (logrusLogger *LogrusLogger) Debugf(format string, args ...interface{}) {
+	logrusLogger.entry().Debugf(format, args...)
+}
+
+func (logrusLogger *LogrusLogger) Infof(format string, args ...interface{}) {
+	logrusLogger.entry().Infof(format, args...)
+}
+
+func (logrusLogger *LogrusLogger) Warnf(format string, args ...interface{}) {
+	logrusLogger.entry().Warnf(format, args...)
+}
+
+func (logrusLogger *LogrusLogger) Errorf(format string, args ...interface{}) {
+	logrusLogger.entry().Errorf(format, args...)
+}
+
+func (logrusLogger *LogrusLogger) entry() *logrusEntry {
+	return &logrusEntry{Entry: logrus.NewEntry(logrusLogger.Logger), levels: logrusLogger.levels}
+}
+
+// logrusEntry logrus entry returning PlLogentry from the With methods, fields are redacted when added.
+// The levels below Fatal are filtered by the level of the module of the entry
+type logrusEntry struct {
+	*logrus.Entry
+	levels *LevelController
+}
+
+func (e *logrusEntry) WithFields(fields map[string]interface{}) PlLogentry {
+	return &logrusEntry{Entry: e.Entry.WithFields(redact.Fields(fields)), levels: e.levels}
+}
+
+func (e *logrusEntry) WithField(key string, value interface{}) PlLogentry {
+	return e.WithFields(map[string]interface{}{key: value})
+}
+
+func (e *logrusEntry) WithError(err error) PlLogentry {
+	return e.WithField(ErrorKey, err)
+}
+
+func (e *logrusEntry) WithContext(ctx context.Context) PlLogentry {
+	return &logrusEntry{Entry: e.Entry.WithContext(ctx).WithFields(redact.Fields(contextFields(ctx))), levels: e.levels}
+}
+
+func (e *logrusEntry) enabled(level logrus.Level) bool {
+	if e.levels == nil {
+		return true // filtered by the logrus level
+	}
+	module, _ := e.Data[ModuleKey].(string)
+	return e.levels.Enabled(module, level)
+}
+
+func (e *logrusEntry) Trace(args ...interface{}) {
+	if e.enabled(logrus.TraceLevel) {
+		e.Entry.Trace(args...)
+	}
+}
+
+func (e *logrusEntry) Debug(args ...interface{}) {
+	if e.enabled(logrus.DebugLevel) {
+		e.Entry.Debug(args...)
+	}
+}
+
+func (e *logrusEntry) Info(args ...interface{}) {
+	if e.enabled(logrus.InfoLevel) {
+		e.Entry.Info(args...)
+	}
+}
+
+func (e *logrusEntry) Warn(args ...interface{}) {
+	if e.enabled(logrus.WarnLevel) {
+		e.Entry.Warn(args...)
+	}
+}
+
+func (e *logrusEntry) Error(args ...interface{}) {
+	if e.enabled(logrus.ErrorLevel) {
+		e.Entry.Error(args...)
+	}
+}
+
+func (e *logrusEntry) Tracef(format string, args ...interface{}) {
+	if e.enabled(logrus.TraceLevel) {
+		e.Entry.Tracef(format, args...)
+	}
+}
+
+func (e *logrusEntry) Debugf(format string, args ...interface{}) {
+	if e.enabled(logrus.DebugLevel) {
+		e.Entry.Debugf(format, args...)
+	}
+}
+
+func (e *logrusEntry) Infof(format string, args ...interface{}) {
+	if e.enabled(logrus.InfoLevel) {
+		e.Entry.Infof(format, args...)
+	}
+}
+
+func (e *logrusEntry) Warnf(format string, args ...interface{}) {
+	if e.enabled(logrus.WarnLevel) {
+		e.Entry.Warnf(format, args...)
+	}
+}
+
+func (e *logrusEntry) Errorf(format string, args ...interface{}) {
+	if e.enabled(logrus.ErrorLevel) {
+		e.Entry.Errorf(format, args...)
+	}
 }
 
 func NewEntry(logger *LogrusLogger) *logrus.Entry {
@@ -1,6 +1,10 @@
 package pllog_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -63,3 +67,28 @@ func TestDisableLogrusLoggerWrapper(t *testing.T) {
 		t.Errorf("Expected default logger but got %s", reflect.TypeOf(logInstance))
 	}
 }
+
+func TestLogrusLoggerFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.Out = &buf
+	l.Formatter = &logrus.JSONFormatter{DisableTimestamp: true}
+	logger := &pllog.LogrusLogger{Logger: l}
+
+	ctx := context.WithValue(context.Background(), pllog.CorrelationID, "cor-1")
+	pllog.CreateLogEntryFromContext(ctx, logger).
+		WithField("user", "jed").
+		WithError(errors.New("no user jed@example.com")).
+		Warn("lookup failed")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["CorrelationId"] != "cor-1" || entry["user"] != "jed" || entry["error"] != "no user [REDACTED]" || entry["msg"] != "lookup failed" {
+		t.Errorf("unexpected entry %s", buf.String())
+	}
+	if _, ok := entry["RequestId"]; ok {
+		t.Errorf("missing context values should not be logged %s", buf.String())
+	}
+}
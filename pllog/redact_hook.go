@@ -0,0 +1,19 @@
+package pllog
+
+import (
+	"github.com/jedrp/go-core/redact"
+	"github.com/sirupsen/logrus"
+)
+
+// RedactHook logrus hook redacting the message of the entries, the fields are redacted by WithFields.
+// Add it before the hooks shipping the entries
+type RedactHook struct{}
+
+func (h *RedactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *RedactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = redact.String(entry.Message)
+	return nil
+}
@@ -0,0 +1,125 @@
+package pllog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Sink destination of the log entries besides Elasticsearch: rotating files, syslog, HTTP collectors
+type Sink interface {
+	// Send write the messages, failures are retried by the buffered hooks unless permanent
+	Send(ctx context.Context, messages []*Message) error
+	Close() error
+}
+
+// PermanentError failure retrying won't fix, the messages are dropped instead of retried or spooled
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// SinkHook logrus hook sending the entries to a sink, synchronously or buffered like ElasticHook
+type SinkHook struct {
+	sink   Sink
+	host   string
+	levels []logrus.Level
+	buffer *logBuffer
+}
+
+// NewSinkHook hook sending each entry of the level or above to the sink when logged
+func NewSinkHook(sink Sink, host string, level logrus.Level) *SinkHook {
+	return &SinkHook{sink: sink, host: host, levels: hookLevels(level)}
+}
+
+// NewBufferedSinkHook hook buffering the entries of the level or above, they are sent
+// in batches retried with backoff then spooled, see BufferOptions.
+// Call Flush or Close before the process exits
+func NewBufferedSinkHook(sink Sink, host string, level logrus.Level, opts BufferOptions) (*SinkHook, error) {
+	hook := NewSinkHook(sink, host, level)
+	buffer, err := newLogBuffer(sinkShipFunc(sink), opts.withDefaults())
+	if err != nil {
+		return nil, err
+	}
+	hook.buffer = buffer
+	return hook, nil
+}
+
+// Levels Required for logrus hook implementation
+func (hook *SinkHook) Levels() []logrus.Level {
+	return hook.levels
+}
+
+// Fire is required to implement Logrus hook
+func (hook *SinkHook) Fire(entry *logrus.Entry) error {
+	msg := newMessage(entry, hook.host)
+	if hook.buffer == nil {
+		return hook.sink.Send(context.Background(), []*Message{msg})
+	}
+	doc, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return hook.buffer.add(spooledDoc{Doc: doc})
+}
+
+// Flush send the buffered entries, no-op for the synchronous hooks
+func (hook *SinkHook) Flush(ctx context.Context) error {
+	if hook.buffer == nil {
+		return nil
+	}
+	return hook.buffer.flush(ctx)
+}
+
+// Close flush the buffered entries until ctx is done then close the sink
+func (hook *SinkHook) Close(ctx context.Context) error {
+	if hook.buffer != nil {
+		if err := hook.buffer.close(ctx); err != nil {
+			hook.sink.Close()
+			return err
+		}
+	}
+	return hook.sink.Close()
+}
+
+// Stats counters of the buffered hooks, zero for the synchronous ones
+func (hook *SinkHook) Stats() BufferStats {
+	if hook.buffer == nil {
+		return BufferStats{}
+	}
+	return hook.buffer.stats()
+}
+
+// sinkShipFunc send the documents to the sink, all of them are retried on failure unless permanent
+func sinkShipFunc(sink Sink) shipFunc {
+	return func(ctx context.Context, docs []spooledDoc) ([]spooledDoc, int, error) {
+		messages := make([]*Message, 0, len(docs))
+		valid := make([]spooledDoc, 0, len(docs))
+		for _, d := range docs {
+			var msg Message
+			if err := json.Unmarshal(d.Doc, &msg); err == nil {
+				messages = append(messages, &msg)
+				valid = append(valid, d)
+			}
+		}
+		rejected := len(docs) - len(valid)
+		err := sink.Send(ctx, messages)
+		var permanent *PermanentError
+		if errors.As(err, &permanent) {
+			return nil, len(docs), err
+		}
+		if err != nil {
+			return valid, rejected, err
+		}
+		return nil, rejected, nil
+	}
+}
@@ -0,0 +1,215 @@
+package pllog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestFileSinkRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	sink, err := NewFileSink(FileOptions{Path: path, MaxSize: 150, MaxBackups: 2, Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	for i := 0; i < 5; i++ {
+		if err := sink.Send(context.Background(), []*Message{{Host: "h", Message: strings.Repeat("x", 50), Level: "INFO"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups kept but got %v", backups)
+	}
+	for _, b := range backups {
+		if !strings.HasSuffix(b, ".gz") {
+			t.Errorf("expected compressed backups but got %s", b)
+		}
+	}
+	b, _ := os.ReadFile(path)
+	if lines := strings.Count(string(b), "\n"); lines != 1 {
+		t.Errorf("expected the last entry in the current file but got %q", b)
+	}
+}
+
+func TestFileSinkRotationFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	var errs []error
+	sink, err := NewFileSink(FileOptions{Path: path, MaxSize: 400, OnError: func(err error) { errs = append(errs, err) }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time { return now }
+	// a directory in place of the rotated file makes the rename fail
+	blocked := path + "." + now.Format("2006-01-02T15-04-05.000")
+	if err := os.Mkdir(blocked, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	send := func(msg string) {
+		if err := sink.Send(context.Background(), []*Message{{Host: "h", Message: msg + strings.Repeat("x", 100), Level: "INFO"}}); err != nil {
+			t.Fatalf("expected %s written but got %v", msg, err)
+		}
+	}
+	send("first")
+	send("second")
+	send("third")
+	if len(errs) != 1 {
+		t.Fatalf("expected the rename failure reported but got %v", errs)
+	}
+	// the next attempt waits for MaxSize more bytes
+	send("fourth")
+	if len(errs) != 1 {
+		t.Errorf("expected no rotation attempt but got %v", errs)
+	}
+
+	os.Remove(blocked)
+	send("fifth")
+	rotated, _ := os.ReadFile(blocked)
+	current, _ := os.ReadFile(path)
+	if strings.Count(string(rotated), "\n") != 4 || !strings.Contains(string(rotated), "fourth") || !strings.Contains(string(current), "fifth") || strings.Count(string(current), "\n") != 1 {
+		t.Errorf("unexpected files after the recovery %q %q", rotated, current)
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	sink, err := NewSyslogSink(SyslogOptions{Network: "udp", Address: conn.LocalAddr().String(), AppName: "orders", Facility: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	msg := &Message{
+		Host:      "api-1",
+		Timestamp: "2020-01-02T03:04:05.123456789Z",
+		Message:   "order created",
+		Fields:    logrus.Fields{"RequestId": "req-1", "note": `a "quoted" [value]`},
+		Level:     "WARNING",
+	}
+	if err := sink.Send(context.Background(), []*Message{msg}); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<132>1 2020-01-02T03:04:05.123456Z api-1 orders ` + sink.pid + ` - [fields@32473 RequestId="req-1" note="a \"quoted\" [value\]"] order created`
+	if string(buf[:n]) != expected {
+		t.Errorf("expected %s but got %s", expected, buf[:n])
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []map[string]interface{}
+	var posted [][]*Message
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		b, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/loki/api/v1/push" {
+			var body map[string]interface{}
+			json.Unmarshal(b, &body)
+			bodies = append(bodies, body)
+		} else {
+			var messages []*Message
+			json.Unmarshal(b, &messages)
+			posted = append(posted, messages)
+		}
+		rw.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	messages := []*Message{
+		{Host: "h", Timestamp: "2020-01-02T03:04:05Z", Message: "one", Level: "INFO"},
+		{Host: "h", Timestamp: "2020-01-02T03:04:06Z", Message: "two", Level: "ERROR"},
+	}
+	sink, _ := NewHTTPSink(HTTPOptions{URL: ts.URL + "/logs"})
+	if err := sink.Send(context.Background(), messages); err != nil {
+		t.Fatal(err)
+	}
+	if len(posted) != 1 || len(posted[0]) != 2 || posted[0][1].Message != "two" {
+		t.Errorf("expected the messages posted as JSON array but got %v", posted)
+	}
+
+	loki, _ := NewHTTPSink(HTTPOptions{URL: ts.URL + "/loki/api/v1/push", Format: HTTPFormatLoki, Labels: map[string]string{"app": "orders"}})
+	if err := loki.Send(context.Background(), messages); err != nil {
+		t.Fatal(err)
+	}
+	streams := bodies[0]["streams"].([]interface{})
+	first := streams[0].(map[string]interface{})
+	labels := first["stream"].(map[string]interface{})
+	values := first["values"].([]interface{})[0].([]interface{})
+	if len(streams) != 2 || labels["level"] != "error" || labels["app"] != "orders" || values[0] != "1577934246000000000" {
+		t.Errorf("expected one loki stream by level but got %v", bodies[0])
+	}
+
+	// rejected batches are dropped, not retried
+	status = http.StatusBadRequest
+	hook, err := NewBufferedSinkHook(sink, "h", logrus.InfoLevel, BufferOptions{RetryBackoff: time.Millisecond, OnError: func(error) {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := logrus.New()
+	log.Out = io.Discard
+	log.Hooks.Add(hook)
+	log.Info("rejected")
+	log.Debug("filtered")
+	hook.Close(context.Background())
+	if s := hook.Stats(); s.Dropped != 1 || s.Sent != 0 || len(posted) != 2 {
+		t.Errorf("expected the entry posted once then dropped but got %+v after %d posts", s, len(posted))
+	}
+}
+
+func TestNewWithRefSinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l := NewWithRef(&LogrusLogger{LogLevel: "debug", LogFile: path, LogFileLevel: "warn", Out: io.Discard})
+	logger, ok := l.(*LogrusLogger)
+	if !ok || len(logger.hooks) != 1 {
+		t.Fatalf("expected a logrus logger with the file sink but got %#v", l)
+	}
+	logger.WithField("password", "p4ss").Warn("disk almost full")
+	logger.Info("filtered by the file level")
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := os.ReadFile(path)
+	var msg Message
+	if err := json.Unmarshal(b, &msg); err != nil {
+		t.Fatalf("expected one JSON line but got %q", b)
+	}
+	if msg.Message != "disk almost full" || msg.Level != "WARNING" || msg.Fields["password"] != "[REDACTED]" {
+		t.Errorf("unexpected message %+v", msg)
+	}
+}
@@ -0,0 +1,271 @@
+package pllog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/jedrp/go-core/redact"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// LevelTrace slog level of logrus.TraceLevel
+	LevelTrace = slog.LevelDebug - 4
+	// LevelFatal slog level of logrus.FatalLevel
+	LevelFatal = slog.LevelError + 4
+	// LevelPanic slog level of logrus.PanicLevel
+	LevelPanic = slog.LevelError + 8
+)
+
+// exit the process after Fatal logs, replaced by tests
+var exit = os.Exit
+
+// SlogLevel slog level of the logrus level
+func SlogLevel(level logrus.Level) slog.Level {
+	switch level {
+	case logrus.TraceLevel:
+		return LevelTrace
+	case logrus.DebugLevel:
+		return slog.LevelDebug
+	case logrus.InfoLevel:
+		return slog.LevelInfo
+	case logrus.WarnLevel:
+		return slog.LevelWarn
+	case logrus.ErrorLevel:
+		return slog.LevelError
+	case logrus.FatalLevel:
+		return LevelFatal
+	default:
+		return LevelPanic
+	}
+}
+
+// LogrusLevel logrus level of the slog level, levels between two logrus levels take the lower one
+func LogrusLevel(level slog.Level) logrus.Level {
+	switch {
+	case level >= LevelPanic:
+		return logrus.PanicLevel
+	case level >= LevelFatal:
+		return logrus.FatalLevel
+	case level >= slog.LevelError:
+		return logrus.ErrorLevel
+	case level >= slog.LevelWarn:
+		return logrus.WarnLevel
+	case level >= slog.LevelInfo:
+		return logrus.InfoLevel
+	case level >= slog.LevelDebug:
+		return logrus.DebugLevel
+	default:
+		return logrus.TraceLevel
+	}
+}
+
+// SlogLogger PlLogger writing to a slog.Handler, fields are slog attributes:
+//
+//	logger := pllog.NewSlogLogger(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: pllog.SlogLevel(logrus.InfoLevel)}))
+type SlogLogger struct {
+	*slogEntry
+}
+
+// NewSlogLogger PlLogger of the handler
+func NewSlogLogger(h slog.Handler) *SlogLogger {
+	return &SlogLogger{&slogEntry{handler: h, ctx: context.Background()}}
+}
+
+// slogEntry entry with the fields bound to the handler, fields are redacted when added
+type slogEntry struct {
+	handler slog.Handler
+	ctx     context.Context
+}
+
+func (e *slogEntry) WithFields(fields map[string]interface{}) PlLogentry {
+	fields = redact.Fields(fields)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, len(keys))
+	for i, k := range keys {
+		attrs[i] = slog.Any(k, fields[k])
+	}
+	return &slogEntry{handler: e.handler.WithAttrs(attrs), ctx: e.ctx}
+}
+
+func (e *slogEntry) WithField(key string, value interface{}) PlLogentry {
+	return e.WithFields(map[string]interface{}{key: value})
+}
+
+func (e *slogEntry) WithError(err error) PlLogentry {
+	return e.WithField(ErrorKey, err)
+}
+
+// WithContext entry with the request and correlation ids of the context, the context is passed to the handler
+func (e *slogEntry) WithContext(ctx context.Context) PlLogentry {
+	entry := e.WithFields(contextFields(ctx)).(*slogEntry)
+	entry.ctx = ctx
+	return entry
+}
+
+// log the record with the caller of the PlLogger method as source, Fatal exit and Panic panic after logging
+func (e *slogEntry) log(level logrus.Level, msg string) {
+	l := SlogLevel(level)
+	msg = redact.String(msg)
+	if e.handler.Enabled(e.ctx, l) {
+		var pcs [1]uintptr
+		runtime.Callers(3, pcs[:]) // skip Callers, log and the level method
+		r := slog.NewRecord(time.Now(), l, msg, pcs[0])
+		e.handler.Handle(e.ctx, r)
+	}
+	switch level {
+	case logrus.FatalLevel:
+		exit(1)
+	case logrus.PanicLevel:
+		panic(msg)
+	}
+}
+
+func (e *slogEntry) Trace(args ...interface{}) {
+	e.log(logrus.TraceLevel, fmt.Sprint(args...))
+}
+func (e *slogEntry) Debug(args ...interface{}) {
+	e.log(logrus.DebugLevel, fmt.Sprint(args...))
+}
+func (e *slogEntry) Info(args ...interface{}) {
+	e.log(logrus.InfoLevel, fmt.Sprint(args...))
+}
+func (e *slogEntry) Warn(args ...interface{}) {
+	e.log(logrus.WarnLevel, fmt.Sprint(args...))
+}
+func (e *slogEntry) Error(args ...interface{}) {
+	e.log(logrus.ErrorLevel, fmt.Sprint(args...))
+}
+func (e *slogEntry) Fatal(args ...interface{}) {
+	e.log(logrus.FatalLevel, fmt.Sprint(args...))
+}
+func (e *slogEntry) Panic(args ...interface{}) {
+	e.log(logrus.PanicLevel, fmt.Sprint(args...))
+}
+func (e *slogEntry) Tracef(format string, args ...interface{}) {
+	e.log(logrus.TraceLevel, fmt.Sprintf(format, args...))
+}
+func (e *slogEntry) Debugf(format string, args ...interface{}) {
+	e.log(logrus.DebugLevel, fmt.Sprintf(format, args...))
+}
+func (e *slogEntry) Infof(format string, args ...interface{}) {
+	e.log(logrus.InfoLevel, fmt.Sprintf(format, args...))
+}
+func (e *slogEntry) Warnf(format string, args ...interface{}) {
+	e.log(logrus.WarnLevel, fmt.Sprintf(format, args...))
+}
+func (e *slogEntry) Errorf(format string, args ...interface{}) {
+	e.log(logrus.ErrorLevel, fmt.Sprintf(format, args...))
+}
+func (e *slogEntry) Fatalf(format string, args ...interface{}) {
+	e.log(logrus.FatalLevel, fmt.Sprintf(format, args...))
+}
+func (e *slogEntry) Panicf(format string, args ...interface{}) {
+	e.log(logrus.PanicLevel, fmt.Sprintf(format, args...))
+}
+
+// plHandler slog.Handler writing the records to a PlLogger
+type plHandler struct {
+	logger PlLogger
+	level  slog.Leveler
+	attrs  []slog.Attr
+	group  string
+}
+
+// NewSlogHandler slog.Handler writing to the PlLogger, for libraries expecting a slog.Logger:
+//
+//	slog.New(pllog.NewSlogHandler(logger, slog.LevelInfo))
+//
+// Records above slog.LevelError are logged as errors, the libraries don't exit or panic the process
+func NewSlogHandler(logger PlLogger, level slog.Leveler) slog.Handler {
+	if level == nil {
+		level = LevelTrace
+	}
+	return &plHandler{logger: logger, level: level}
+}
+
+func (h *plHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *plHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := map[string]interface{}{}
+	for _, a := range h.attrs {
+		addAttr(fields, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(fields, h.group, a)
+		return true
+	})
+	entry := h.logger.WithContext(ctx)
+	if len(fields) > 0 {
+		entry = entry.WithFields(fields)
+	}
+	switch LogrusLevel(r.Level) {
+	case logrus.TraceLevel:
+		entry.Trace(r.Message)
+	case logrus.DebugLevel:
+		entry.Debug(r.Message)
+	case logrus.InfoLevel:
+		entry.Info(r.Message)
+	case logrus.WarnLevel:
+		entry.Warn(r.Message)
+	default:
+		entry.Error(r.Message)
+	}
+	return nil
+}
+
+func (h *plHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		if h.group != "" {
+			a.Key = h.group + "." + a.Key
+		}
+		c.attrs = append(c.attrs, a)
+	}
+	return &c
+}
+
+func (h *plHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	if h.group != "" {
+		name = h.group + "." + name
+	}
+	c.group = name
+	return &c
+}
+
+// addAttr add the attribute as field, groups are flattened with dotted keys
+func addAttr(fields map[string]interface{}, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	key := a.Key
+	if prefix != "" && key != "" {
+		key = prefix + "." + key
+	} else if key == "" {
+		key = prefix
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			addAttr(fields, key, ga)
+		}
+		return
+	}
+	fields[key] = a.Value.Any()
+}
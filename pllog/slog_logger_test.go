@@ -0,0 +1,86 @@
+package pllog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/jedrp/go-core/pllog"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := pllog.NewSlogLogger(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true, Level: pllog.SlogLevel(logrus.DebugLevel)}))
+
+	ctx := context.WithValue(context.Background(), pllog.RequestID, "req-1")
+	logger.WithContext(ctx).WithFields(map[string]interface{}{"user": "jed", "password": "p4ss"}).WithError(errors.New("boom")).Warnf("get %s", "user")
+	logger.Trace("hidden")
+	logger.Debug("visible")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records but got %s", buf.String())
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record["level"] != "WARN" || record["msg"] != "get user" || record["RequestId"] != "req-1" || record["user"] != "jed" || record["password"] != "[REDACTED]" || record["error"] != "boom" {
+		t.Errorf("unexpected record %s", lines[0])
+	}
+	if source, _ := record["source"].(map[string]interface{}); source == nil || !strings.HasSuffix(source["file"].(string), "slog_logger_test.go") {
+		t.Errorf("expected the caller as source but got %v", record["source"])
+	}
+
+	if pllog.LogrusLevel(pllog.SlogLevel(logrus.FatalLevel)) != logrus.FatalLevel || pllog.LogrusLevel(slog.LevelInfo+2) != logrus.InfoLevel {
+		t.Error("unexpected level mapping")
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.Out = &buf
+	l.Formatter = &logrus.JSONFormatter{DisableTimestamp: true}
+	logger := slog.New(pllog.NewSlogHandler(&pllog.LogrusLogger{Logger: l}, slog.LevelInfo))
+
+	logger.With("component", "db").WithGroup("query").Error("slow query", "ms", 1200, slog.Group("table", "name", "users"))
+	logger.Debug("hidden")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a single entry but got %s", buf.String())
+	}
+	if entry["level"] != "error" || entry["msg"] != "slow query" || entry["component"] != "db" || entry["query.ms"] != float64(1200) || entry["query.table.name"] != "users" {
+		t.Errorf("unexpected entry %s", buf.String())
+	}
+}
+
+func TestSlogLoggerFatal(t *testing.T) {
+	var buf bytes.Buffer
+	l := pllog.NewSlogLogger(slog.NewTextHandler(&buf, nil))
+
+	code := 0
+	defer pllog.SetExit(func(c int) { code = c })()
+	l.WithField("job", "import").Fatalf("stopping %s", "now")
+	if code != 1 || !strings.Contains(buf.String(), "stopping now") {
+		t.Errorf("expected the message logged then exit 1 as DefaultLogger but got %d %q", code, buf.String())
+	}
+}
+
+func TestSlogLoggerPanicRedacted(t *testing.T) {
+	var buf bytes.Buffer
+	l := pllog.NewSlogLogger(slog.NewTextHandler(&buf, nil))
+	defer func() {
+		r := recover()
+		if msg, _ := r.(string); strings.Contains(msg, "jed@example.com") || !strings.Contains(msg, "[REDACTED]") {
+			t.Errorf("expected the panic with the redacted message but got %v", r)
+		}
+	}()
+	l.Panicf("no account for %s", "jed@example.com")
+}
@@ -0,0 +1,192 @@
+package pllog
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// syslogEnterpriseID private enterprise number of the structured data of the fields, the example one of RFC 5424
+	syslogEnterpriseID = "32473"
+	syslogTimeout      = 5 * time.Second
+)
+
+// SyslogOptions destination of the syslog sink
+type SyslogOptions struct {
+	Network  string // udp, tcp, unix (stream) or unixgram
+	Address  string // host:port or socket path
+	AppName  string
+	Hostname string // host of the messages when empty, os.Hostname without
+	Facility int    // 1 (user) by default, 16 to 23 for local0 to local7
+}
+
+// ParseSyslogAddress options of an address as network://address, e.g. udp://localhost:514, tcp://syslog:601 or unixgram:///dev/log
+func ParseSyslogAddress(address string) (SyslogOptions, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return SyslogOptions{}, err
+	}
+	switch u.Scheme {
+	case "udp", "tcp":
+		return SyslogOptions{Network: u.Scheme, Address: u.Host}, nil
+	case "unix", "unixgram":
+		return SyslogOptions{Network: u.Scheme, Address: u.Path}, nil
+	}
+	return SyslogOptions{}, fmt.Errorf("not a valid syslog address: %q", address)
+}
+
+// SyslogSink sink sending RFC 5424 messages, the fields are the structured data. Stream connections
+// frame the messages by octet counting (RFC 6587), the connection is dialed again after a failure
+type SyslogSink struct {
+	opts SyslogOptions
+	pid  string
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogSink sink of the options, the connection is dialed by the first Send
+func NewSyslogSink(opts SyslogOptions) (*SyslogSink, error) {
+	switch opts.Network {
+	case "udp", "tcp", "unix", "unixgram":
+	default:
+		return nil, fmt.Errorf("not a valid syslog network: %q", opts.Network)
+	}
+	if opts.Facility <= 0 || opts.Facility > 23 {
+		opts.Facility = 1
+	}
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+	return &SyslogSink{opts: opts, pid: strconv.Itoa(os.Getpid())}, nil
+}
+
+func (s *SyslogSink) Send(ctx context.Context, messages []*Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		d := net.Dialer{Timeout: syslogTimeout}
+		conn, err := d.DialContext(ctx, s.opts.Network, s.opts.Address)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+	stream := s.opts.Network == "tcp" || s.opts.Network == "unix"
+	s.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+	for _, m := range messages {
+		line := s.Format(m)
+		if stream {
+			line = strconv.Itoa(len(line)) + " " + line
+		}
+		if _, err := s.conn.Write([]byte(line)); err != nil {
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// Format RFC 5424 message: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [fields@32473 key="value"...] MSG
+func (s *SyslogSink) Format(m *Message) string {
+	timestamp := "-"
+	if t, err := time.Parse(time.RFC3339Nano, m.Timestamp); err == nil {
+		timestamp = t.Format("2006-01-02T15:04:05.000000Z07:00")
+	}
+	hostname := s.opts.Hostname
+	if m.Host != "" {
+		hostname = m.Host
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %s - %s %s",
+		s.opts.Facility*8+syslogSeverity(m.Level),
+		timestamp,
+		syslogHeaderField(hostname, 255),
+		syslogHeaderField(s.opts.AppName, 48),
+		s.pid,
+		syslogStructuredData(m.Fields),
+		m.Message)
+}
+
+// syslogSeverity severity of the level names of the messages
+func syslogSeverity(level string) int {
+	switch strings.ToLower(level) {
+	case "panic":
+		return 1 // alert
+	case "fatal":
+		return 2 // critical
+	case "error":
+		return 3
+	case "warning", "warn":
+		return 4
+	case "info":
+		return 6
+	default:
+		return 7 // debug
+	}
+}
+
+// syslogHeaderField printable US-ASCII without spaces, - when empty
+func syslogHeaderField(v string, max int) string {
+	v = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, v)
+	if v == "" {
+		return "-"
+	}
+	if len(v) > max {
+		v = v[:max]
+	}
+	return v
+}
+
+func syslogStructuredData(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString("[fields@" + syslogEnterpriseID)
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	for _, k := range keys {
+		name := strings.Map(func(r rune) rune {
+			if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+				return '_'
+			}
+			return r
+		}, k)
+		if len(name) > 32 {
+			name = name[:32]
+		} else if name == "" {
+			name = "_"
+		}
+		b.WriteString(" " + name + `="` + escaper.Replace(fmt.Sprint(fields[k])) + `"`)
+	}
+	b.WriteString("]")
+	return b.String()
+}
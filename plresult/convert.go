@@ -0,0 +1,73 @@
+package plresult
+
+import (
+	"errors"
+
+	"github.com/jedrp/go-core/infras"
+)
+
+// ErrorDomain ErrorInfo domain of errors converted from plresult
+const ErrorDomain = "plresult"
+
+// ToInfras convert to infras.Result, the error code and params (see infras.ParamsMetadata) travel in the
+// ErrorInfo detail and the field violations in the BadRequest detail. The origin error is kept server side in the Cause
+func ToInfras(r *Result) *infras.Result {
+	if r.IsSuccess {
+		return &infras.Result{Value: r.Value, Meta: r.Meta}
+	}
+	e := r.Error
+	res := infras.Fail(CodeOf(e), e.GetErrorMessage()).
+		WithErrorInfo(e.GetCode(), ErrorDomain, infras.ParamsMetadata(ParamsOf(e))).
+		WithFieldViolations(FieldViolationsOf(e)...)
+	res.Meta = r.Meta
+	res.Cause = e.GetOriginError()
+	return res
+}
+
+// FromInfras convert to Result, the error kind is taken from the status code and
+// the error code from the ErrorInfo reason (canonical code name when missing),
+// BadRequest field violations become the ValidationErrors origin error, the Cause otherwise
+// and the status message when there is none
+func FromInfras(r *infras.Result) *Result {
+	if r.Error == nil {
+		return &Result{IsSuccess: true, Value: r.Value, Meta: r.Meta}
+	}
+	errorCode := infras.CodeName(r.Error.Code())
+	origin := r.Cause
+	if origin == nil {
+		origin = errors.New(r.Error.Message())
+	}
+	var params map[string]string
+	if info := r.ErrorInfo(); info != nil {
+		errorCode = info.Type
+		params = infras.ParamsFromMetadata(info.Metadata)
+	}
+	if violations := infras.FieldViolationsOf(r.Error); len(violations) > 0 {
+		origin = &ValidationErrors{Violations: violations}
+	}
+	result := ErrorResult(r.Error.Code(), origin, errorCode, r.Error.Message())
+	if len(params) > 0 {
+		WithParams(result.Error, params)
+	}
+	result.Meta = r.Meta
+	return result
+}
+
+// GetGrpcError gRPC status error of the error kind following the errorKinds taxonomy,
+// the error code travels in the ErrorInfo detail, see FromGrpcError for the reverse.
+// As before the taxonomy, clients get the error message only, never the origin error
+func GetGrpcError(e Error) error {
+	if e == nil {
+		return nil
+	}
+	return ToInfras(&Result{IsSuccess: false, Error: e}).Err()
+}
+
+// FromGrpcError error of the kind of the gRPC status code, for client side use of errors
+// returned by GetGrpcError, nil when the err is nil
+func FromGrpcError(err error) Error {
+	if err == nil {
+		return nil
+	}
+	return FromInfras(infras.FromError(err)).Error
+}
@@ -1,11 +1,14 @@
 package plresult
 
 import (
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"fmt"
 )
 
+// Error wrapped error, every kind implement error and unwrap to the origin error so
+// errors.Is / errors.As work through it. errors.Is compare to sentinels of the same kind by error code,
+// a sentinel without code (e.g. &NotFoundError{}) match every error of its kind.
 type Error interface {
+	error
 	GetCode() string
 	GetOriginError() error
 	GetErrorMessage() string
@@ -14,14 +17,39 @@ type Error interface {
 	SetMessage(msg string)
 }
 
-//Error wrapper
+var (
+	// ErrValidation sentinel matching every ValidationError with errors.Is
+	ErrValidation = &ValidationError{}
+	// ErrNotFound sentinel matching every NotFoundError with errors.Is
+	ErrNotFound = &NotFoundError{}
+	// ErrInternalServer sentinel matching every InternalServerError with errors.Is
+	ErrInternalServer = &InternalServerError{}
+	// ErrUnknown sentinel matching every UnkownError with errors.Is
+	ErrUnknown = &UnkownError{}
+	// ErrConflict sentinel matching every ConflictError with errors.Is
+	ErrConflict = &ConflictError{}
+	// ErrUnauthorized sentinel matching every UnauthorizedError with errors.Is
+	ErrUnauthorized = &UnauthorizedError{}
+	// ErrForbidden sentinel matching every ForbiddenError with errors.Is
+	ErrForbidden = &ForbiddenError{}
+	// ErrPreconditionFailed sentinel matching every PreconditionFailedError with errors.Is
+	ErrPreconditionFailed = &PreconditionFailedError{}
+	// ErrTooManyRequests sentinel matching every TooManyRequestsError with errors.Is
+	ErrTooManyRequests = &TooManyRequestsError{}
+	// ErrUnavailable sentinel matching every UnavailableError with errors.Is
+	ErrUnavailable = &UnavailableError{}
+)
+
+// Error wrapper
 type errorObj struct {
-	OriginError  error  //the origin error causing problem
-	ErrorCode    string //System specific error code
-	ErrorMessage string //System specfic error message
+	OriginError  error             //the origin error causing problem
+	ErrorCode    string            //System specific error code
+	ErrorMessage string            //System specfic error message
+	Params       map[string]string // message catalog template params
+	stack        []uintptr
 }
 
-//ValidationError ..
+// ValidationError ..
 type ValidationError errorObj
 
 func (err *ValidationError) GetCode() string {
@@ -43,7 +71,32 @@ func (err *ValidationError) SetMessage(msg string) {
 	err.ErrorMessage = msg
 }
 
-//NotFoundError ..
+func (err *ValidationError) Error() string {
+	return errorString(err.ErrorMessage, err.OriginError)
+}
+
+func (err *ValidationError) Unwrap() error {
+	return err.OriginError
+}
+
+func (err *ValidationError) Is(target error) bool {
+	t, ok := target.(*ValidationError)
+	return ok && matchCode(t.ErrorCode, err.ErrorCode)
+}
+
+func (err *ValidationError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, err.ErrorCode, err.ErrorMessage, err.OriginError, err.stack)
+}
+
+func (err *ValidationError) setStack(stack []uintptr) {
+	err.stack = stack
+}
+
+func (err *ValidationError) params() *map[string]string {
+	return &err.Params
+}
+
+// NotFoundError ..
 type NotFoundError errorObj
 
 func (err *NotFoundError) GetCode() string {
@@ -70,7 +123,32 @@ func (err *NotFoundError) SetMessage(msg string) {
 	err.ErrorMessage = msg
 }
 
-//InternalServerError ..
+func (err *NotFoundError) Error() string {
+	return errorString(err.ErrorMessage, err.OriginError)
+}
+
+func (err *NotFoundError) Unwrap() error {
+	return err.OriginError
+}
+
+func (err *NotFoundError) Is(target error) bool {
+	t, ok := target.(*NotFoundError)
+	return ok && matchCode(t.ErrorCode, err.ErrorCode)
+}
+
+func (err *NotFoundError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, err.ErrorCode, err.ErrorMessage, err.OriginError, err.stack)
+}
+
+func (err *NotFoundError) setStack(stack []uintptr) {
+	err.stack = stack
+}
+
+func (err *NotFoundError) params() *map[string]string {
+	return &err.Params
+}
+
+// InternalServerError ..
 type InternalServerError errorObj
 
 func (err *InternalServerError) GetCode() string {
@@ -97,7 +175,32 @@ func (err *InternalServerError) SetMessage(msg string) {
 	err.ErrorMessage = msg
 }
 
-//UnkownError ..
+func (err *InternalServerError) Error() string {
+	return errorString(err.ErrorMessage, err.OriginError)
+}
+
+func (err *InternalServerError) Unwrap() error {
+	return err.OriginError
+}
+
+func (err *InternalServerError) Is(target error) bool {
+	t, ok := target.(*InternalServerError)
+	return ok && matchCode(t.ErrorCode, err.ErrorCode)
+}
+
+func (err *InternalServerError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, err.ErrorCode, err.ErrorMessage, err.OriginError, err.stack)
+}
+
+func (err *InternalServerError) setStack(stack []uintptr) {
+	err.stack = stack
+}
+
+func (err *InternalServerError) params() *map[string]string {
+	return &err.Params
+}
+
+// UnkownError ..
 type UnkownError errorObj
 
 func (err *UnkownError) GetCode() string {
@@ -124,20 +227,341 @@ func (err *UnkownError) SetMessage(msg string) {
 	err.ErrorMessage = msg
 }
 
-func GetGrpcError(e Error) error {
-	if e != nil {
-		var code codes.Code
-		switch e.(type) {
-		case *ValidationError:
-			code = codes.InvalidArgument
-		case *NotFoundError:
-			code = codes.InvalidArgument
-		default:
-			code = codes.InvalidArgument
-		}
-		return status.Errorf(code, e.GetErrorMessage())
-	}
-	return nil
+func (err *UnkownError) Error() string {
+	return errorString(err.ErrorMessage, err.OriginError)
+}
+
+func (err *UnkownError) Unwrap() error {
+	return err.OriginError
+}
+
+func (err *UnkownError) Is(target error) bool {
+	t, ok := target.(*UnkownError)
+	return ok && matchCode(t.ErrorCode, err.ErrorCode)
+}
+
+func (err *UnkownError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, err.ErrorCode, err.ErrorMessage, err.OriginError, err.stack)
+}
+
+func (err *UnkownError) setStack(stack []uintptr) {
+	err.stack = stack
+}
+
+func (err *UnkownError) params() *map[string]string {
+	return &err.Params
+}
+
+// ConflictError ..
+type ConflictError errorObj
+
+func (err *ConflictError) GetCode() string {
+	return err.ErrorCode
+}
+
+func (err *ConflictError) GetOriginError() error {
+	return err.OriginError
+}
+
+func (err *ConflictError) GetErrorMessage() string {
+	return err.ErrorMessage
+}
+
+func (err *ConflictError) SetCode(code string) {
+	err.ErrorCode = code
+}
+
+func (err *ConflictError) SetError(orgError error) {
+	err.OriginError = orgError
+}
+
+func (err *ConflictError) SetMessage(msg string) {
+	err.ErrorMessage = msg
+}
+
+func (err *ConflictError) Error() string {
+	return errorString(err.ErrorMessage, err.OriginError)
+}
+
+func (err *ConflictError) Unwrap() error {
+	return err.OriginError
+}
+
+func (err *ConflictError) Is(target error) bool {
+	t, ok := target.(*ConflictError)
+	return ok && matchCode(t.ErrorCode, err.ErrorCode)
+}
+
+func (err *ConflictError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, err.ErrorCode, err.ErrorMessage, err.OriginError, err.stack)
+}
+
+func (err *ConflictError) setStack(stack []uintptr) {
+	err.stack = stack
+}
+
+func (err *ConflictError) params() *map[string]string {
+	return &err.Params
+}
+
+// UnauthorizedError ..
+type UnauthorizedError errorObj
+
+func (err *UnauthorizedError) GetCode() string {
+	return err.ErrorCode
+}
+
+func (err *UnauthorizedError) GetOriginError() error {
+	return err.OriginError
+}
+
+func (err *UnauthorizedError) GetErrorMessage() string {
+	return err.ErrorMessage
+}
+
+func (err *UnauthorizedError) SetCode(code string) {
+	err.ErrorCode = code
+}
+
+func (err *UnauthorizedError) SetError(orgError error) {
+	err.OriginError = orgError
+}
+
+func (err *UnauthorizedError) SetMessage(msg string) {
+	err.ErrorMessage = msg
+}
+
+func (err *UnauthorizedError) Error() string {
+	return errorString(err.ErrorMessage, err.OriginError)
+}
+
+func (err *UnauthorizedError) Unwrap() error {
+	return err.OriginError
+}
+
+func (err *UnauthorizedError) Is(target error) bool {
+	t, ok := target.(*UnauthorizedError)
+	return ok && matchCode(t.ErrorCode, err.ErrorCode)
+}
+
+func (err *UnauthorizedError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, err.ErrorCode, err.ErrorMessage, err.OriginError, err.stack)
+}
+
+func (err *UnauthorizedError) setStack(stack []uintptr) {
+	err.stack = stack
+}
+
+func (err *UnauthorizedError) params() *map[string]string {
+	return &err.Params
+}
+
+// ForbiddenError ..
+type ForbiddenError errorObj
+
+func (err *ForbiddenError) GetCode() string {
+	return err.ErrorCode
+}
+
+func (err *ForbiddenError) GetOriginError() error {
+	return err.OriginError
+}
+
+func (err *ForbiddenError) GetErrorMessage() string {
+	return err.ErrorMessage
+}
+
+func (err *ForbiddenError) SetCode(code string) {
+	err.ErrorCode = code
+}
+
+func (err *ForbiddenError) SetError(orgError error) {
+	err.OriginError = orgError
+}
+
+func (err *ForbiddenError) SetMessage(msg string) {
+	err.ErrorMessage = msg
+}
+
+func (err *ForbiddenError) Error() string {
+	return errorString(err.ErrorMessage, err.OriginError)
+}
+
+func (err *ForbiddenError) Unwrap() error {
+	return err.OriginError
+}
+
+func (err *ForbiddenError) Is(target error) bool {
+	t, ok := target.(*ForbiddenError)
+	return ok && matchCode(t.ErrorCode, err.ErrorCode)
+}
+
+func (err *ForbiddenError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, err.ErrorCode, err.ErrorMessage, err.OriginError, err.stack)
+}
+
+func (err *ForbiddenError) setStack(stack []uintptr) {
+	err.stack = stack
+}
+
+func (err *ForbiddenError) params() *map[string]string {
+	return &err.Params
+}
+
+// PreconditionFailedError ..
+type PreconditionFailedError errorObj
+
+func (err *PreconditionFailedError) GetCode() string {
+	return err.ErrorCode
+}
+
+func (err *PreconditionFailedError) GetOriginError() error {
+	return err.OriginError
+}
+
+func (err *PreconditionFailedError) GetErrorMessage() string {
+	return err.ErrorMessage
+}
+
+func (err *PreconditionFailedError) SetCode(code string) {
+	err.ErrorCode = code
+}
+
+func (err *PreconditionFailedError) SetError(orgError error) {
+	err.OriginError = orgError
+}
+
+func (err *PreconditionFailedError) SetMessage(msg string) {
+	err.ErrorMessage = msg
+}
+
+func (err *PreconditionFailedError) Error() string {
+	return errorString(err.ErrorMessage, err.OriginError)
+}
+
+func (err *PreconditionFailedError) Unwrap() error {
+	return err.OriginError
+}
+
+func (err *PreconditionFailedError) Is(target error) bool {
+	t, ok := target.(*PreconditionFailedError)
+	return ok && matchCode(t.ErrorCode, err.ErrorCode)
+}
+
+func (err *PreconditionFailedError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, err.ErrorCode, err.ErrorMessage, err.OriginError, err.stack)
+}
+
+func (err *PreconditionFailedError) setStack(stack []uintptr) {
+	err.stack = stack
+}
+
+func (err *PreconditionFailedError) params() *map[string]string {
+	return &err.Params
+}
+
+// TooManyRequestsError ..
+type TooManyRequestsError errorObj
+
+func (err *TooManyRequestsError) GetCode() string {
+	return err.ErrorCode
+}
+
+func (err *TooManyRequestsError) GetOriginError() error {
+	return err.OriginError
+}
+
+func (err *TooManyRequestsError) GetErrorMessage() string {
+	return err.ErrorMessage
+}
+
+func (err *TooManyRequestsError) SetCode(code string) {
+	err.ErrorCode = code
+}
+
+func (err *TooManyRequestsError) SetError(orgError error) {
+	err.OriginError = orgError
+}
+
+func (err *TooManyRequestsError) SetMessage(msg string) {
+	err.ErrorMessage = msg
+}
+
+func (err *TooManyRequestsError) Error() string {
+	return errorString(err.ErrorMessage, err.OriginError)
+}
+
+func (err *TooManyRequestsError) Unwrap() error {
+	return err.OriginError
+}
+
+func (err *TooManyRequestsError) Is(target error) bool {
+	t, ok := target.(*TooManyRequestsError)
+	return ok && matchCode(t.ErrorCode, err.ErrorCode)
+}
+
+func (err *TooManyRequestsError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, err.ErrorCode, err.ErrorMessage, err.OriginError, err.stack)
+}
+
+func (err *TooManyRequestsError) setStack(stack []uintptr) {
+	err.stack = stack
+}
+
+func (err *TooManyRequestsError) params() *map[string]string {
+	return &err.Params
+}
+
+// UnavailableError ..
+type UnavailableError errorObj
+
+func (err *UnavailableError) GetCode() string {
+	return err.ErrorCode
+}
+
+func (err *UnavailableError) GetOriginError() error {
+	return err.OriginError
+}
+
+func (err *UnavailableError) GetErrorMessage() string {
+	return err.ErrorMessage
+}
+
+func (err *UnavailableError) SetCode(code string) {
+	err.ErrorCode = code
+}
+
+func (err *UnavailableError) SetError(orgError error) {
+	err.OriginError = orgError
+}
+
+func (err *UnavailableError) SetMessage(msg string) {
+	err.ErrorMessage = msg
+}
+
+func (err *UnavailableError) Error() string {
+	return errorString(err.ErrorMessage, err.OriginError)
+}
+
+func (err *UnavailableError) Unwrap() error {
+	return err.OriginError
+}
+
+func (err *UnavailableError) Is(target error) bool {
+	t, ok := target.(*UnavailableError)
+	return ok && matchCode(t.ErrorCode, err.ErrorCode)
+}
+
+func (err *UnavailableError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, err.ErrorCode, err.ErrorMessage, err.OriginError, err.stack)
+}
+
+func (err *UnavailableError) setStack(stack []uintptr) {
+	err.stack = stack
+}
+
+func (err *UnavailableError) params() *map[string]string {
+	return &err.Params
 }
 
 func NewValidationError(err error, opts ...string) Error {
@@ -152,8 +576,35 @@ func NewNotFoundError(err error, opts ...string) Error {
 	return newErrorResult(&NotFoundError{}, err, opts)
 }
 
+func NewConflictError(err error, opts ...string) Error {
+	return newErrorResult(&ConflictError{}, err, opts)
+}
+
+func NewUnauthorizedError(err error, opts ...string) Error {
+	return newErrorResult(&UnauthorizedError{}, err, opts)
+}
+
+func NewForbiddenError(err error, opts ...string) Error {
+	return newErrorResult(&ForbiddenError{}, err, opts)
+}
+
+func NewPreconditionFailedError(err error, opts ...string) Error {
+	return newErrorResult(&PreconditionFailedError{}, err, opts)
+}
+
+func NewTooManyRequestsError(err error, opts ...string) Error {
+	return newErrorResult(&TooManyRequestsError{}, err, opts)
+}
+
+func NewUnavailableError(err error, opts ...string) Error {
+	return newErrorResult(&UnavailableError{}, err, opts)
+}
+
 func newErrorResult(errWrapper Error, err error, opts []string) Error {
 	optsLength := len(opts)
+	if st, ok := errWrapper.(stackTracer); ok {
+		st.setStack(captureStack(2))
+	}
 	errWrapper.SetError(err)
 	switch {
 	case optsLength > 1:
@@ -0,0 +1,69 @@
+package plresult
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+const maxStackDepth = 32
+
+// stackTracer implemented by the error kinds to record the construction stack
+type stackTracer interface {
+	setStack(stack []uintptr)
+}
+
+func captureStack(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+2, pcs)
+	return pcs[:n]
+}
+
+func errorString(message string, origin error) string {
+	if message != "" {
+		return message
+	}
+	if origin != nil {
+		return origin.Error()
+	}
+	return ""
+}
+
+// matchCode sentinel without error code match every error of its kind
+func matchCode(sentinelCode string, code string) bool {
+	return sentinelCode == "" || sentinelCode == code
+}
+
+// formatError %+v print the error code, message, the chain of origin errors and the construction stack
+func formatError(s fmt.State, verb rune, code string, message string, origin error, stack []uintptr) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%s: %s", code, errorString(message, origin))
+			if origin != nil {
+				fmt.Fprintf(s, "\ncaused by: %+v", origin)
+			}
+			writeStack(s, stack)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, errorString(message, origin))
+	case 'q':
+		fmt.Fprintf(s, "%q", errorString(message, origin))
+	}
+}
+
+func writeStack(w io.Writer, stack []uintptr) {
+	if len(stack) == 0 {
+		return
+	}
+	frames := runtime.CallersFrames(stack)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(w, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+		if !more {
+			return
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package plresult_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jedrp/go-core/plresult"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorsIsAs(t *testing.T) {
+	origin := fmt.Errorf("query user: %w", io.EOF)
+	err := fmt.Errorf("handler: %w", plresult.NewNotFoundError(origin, "USER_NOT_FOUND", "user not found"))
+
+	if !errors.Is(err, io.EOF) {
+		t.Error("expected to unwrap to the origin error")
+	}
+	if !errors.Is(err, plresult.ErrNotFound) {
+		t.Error("expected to match the kind sentinel")
+	}
+	if !errors.Is(err, &plresult.NotFoundError{ErrorCode: "USER_NOT_FOUND"}) {
+		t.Error("expected to match the sentinel of the same code")
+	}
+	if errors.Is(err, &plresult.NotFoundError{ErrorCode: "ORDER_NOT_FOUND"}) || errors.Is(err, plresult.ErrValidation) {
+		t.Error("should not match sentinel of another code or kind")
+	}
+	if !errors.Is(plresult.NewErrorFromCode(codes.DataLoss, origin), &plresult.StatusError{Code: codes.DataLoss}) {
+		t.Error("expected StatusError to match by canonical code")
+	}
+
+	var notFound *plresult.NotFoundError
+	if !errors.As(err, &notFound) || notFound.GetCode() != "USER_NOT_FOUND" {
+		t.Errorf("expected errors.As to find the NotFoundError but got %v", notFound)
+	}
+	if err.Error() != "handler: user not found" {
+		t.Errorf("unexpected message %s", err.Error())
+	}
+}
+
+func TestErrorFormat(t *testing.T) {
+	err := plresult.NewValidationError(fmt.Errorf("parse: %w", io.ErrUnexpectedEOF), "BAD_JSON", "invalid body")
+
+	if s := fmt.Sprintf("%v", err); s != "invalid body" {
+		t.Errorf("unexpected %%v %s", s)
+	}
+	detailed := fmt.Sprintf("%+v", err)
+	for _, expected := range []string{"BAD_JSON: invalid body", "caused by: parse: unexpected EOF", "plresult_test.TestErrorFormat"} {
+		if !strings.Contains(detailed, expected) {
+			t.Errorf("expected %%+v to contain %q but got %s", expected, detailed)
+		}
+	}
+}
+
+func TestGrpcErrorRoundTrip(t *testing.T) {
+	tt := []struct {
+		err          plresult.Error
+		expectedCode codes.Code
+	}{
+		{plresult.NewValidationError(errors.New("Jed_Test"), "NAME_REQUIRED", "name is required"), codes.InvalidArgument},
+		{plresult.NewNotFoundError(errors.New("Jed_Test"), "USER_NOT_FOUND", "user not found"), codes.NotFound},
+		{plresult.NewInternalServerError(errors.New("Jed_Test"), "DB_DOWN", "internal error"), codes.Internal},
+		{plresult.NewConflictError(errors.New("Jed_Test"), "USER_EXISTS", "user exists"), codes.AlreadyExists},
+		{plresult.NewErrorFromCode(codes.DataLoss, errors.New("Jed_Test"), "CORRUPTED", "data corrupted"), codes.DataLoss},
+	}
+
+	for _, tc := range tt {
+		grpcErr := plresult.GetGrpcError(tc.err)
+		if code := status.Code(grpcErr); code != tc.expectedCode {
+			t.Errorf("expected %T to map to %v but got %v", tc.err, tc.expectedCode, code)
+		}
+		back := plresult.FromGrpcError(grpcErr)
+		if fmt.Sprintf("%T", back) != fmt.Sprintf("%T", tc.err) {
+			t.Errorf("expected %T back but got %T", tc.err, back)
+		}
+		if back.GetCode() != tc.err.GetCode() || back.GetErrorMessage() != tc.err.GetErrorMessage() {
+			t.Errorf("expected %s %s back but got %s %s", tc.err.GetCode(), tc.err.GetErrorMessage(), back.GetCode(), back.GetErrorMessage())
+		}
+		if back.GetOriginError().Error() != tc.err.GetErrorMessage() {
+			t.Errorf("expected the status message as origin error but got %v", back.GetOriginError())
+		}
+	}
+
+	origin := errors.New("pq: connection to 10.0.0.5:5432 refused for user billing_rw")
+	s := status.Convert(plresult.GetGrpcError(plresult.NewInternalServerError(origin, "DB_DOWN", "internal error")))
+	if strings.Contains(fmt.Sprint(s.Details()), "10.0.0.5") || strings.Contains(s.Message(), "10.0.0.5") {
+		t.Errorf("the origin error leaked to the gRPC client: %s %v", s.Message(), s.Details())
+	}
+
+	if plresult.GetGrpcError(nil) != nil || plresult.FromGrpcError(nil) != nil {
+		t.Error("nil error should stay nil")
+	}
+	if _, ok := plresult.FromGrpcError(status.Error(codes.NotFound, "missing")).(*plresult.NotFoundError); !ok {
+		t.Error("expected status without ErrorInfo to map by code")
+	}
+}
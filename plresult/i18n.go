@@ -0,0 +1,42 @@
+package plresult
+
+import (
+	"context"
+
+	"github.com/jedrp/go-core/infras"
+)
+
+// paramsHolder implemented by the error kinds to keep the message catalog template params
+type paramsHolder interface {
+	params() *map[string]string
+}
+
+// WithParams set the params interpolated in the message catalog template of the error code
+//
+//	plresult.WithParams(plresult.NewNotFoundError(err, "USER_NOT_FOUND", "user not found"), map[string]string{"id": id})
+func WithParams(e Error, params map[string]string) Error {
+	if h, ok := e.(paramsHolder); ok {
+		p := h.params()
+		if *p == nil {
+			*p = make(map[string]string, len(params))
+		}
+		for k, v := range params {
+			(*p)[k] = v
+		}
+	}
+	return e
+}
+
+// ParamsOf message catalog template params of the error
+func ParamsOf(e Error) map[string]string {
+	if h, ok := e.(paramsHolder); ok {
+		return *h.params()
+	}
+	return nil
+}
+
+// Localize message of the error code in the locales of the context from infras.Messages,
+// the error message is kept for logs and returned when the catalog has none
+func Localize(ctx context.Context, e Error) string {
+	return infras.Messages.Localize(ctx, e.GetCode(), ParamsOf(e), e.GetErrorMessage())
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/go-openapi/runtime"
+	"github.com/jedrp/go-core/infras"
+	"github.com/jedrp/go-core/redact"
 )
 
 //Result Wrapper struct
@@ -11,6 +13,7 @@ type Result struct {
 	IsSuccess bool
 	Value     interface{}
 	Error     Error
+	Meta      *infras.ResponseMeta //status override, headers and cookies of the HTTP response
 }
 
 func NewResult(s interface{}, e Error) *Result {
@@ -34,6 +37,40 @@ func OKResult(value interface{}) *Result {
 	}
 }
 
+//CreatedResult 201 Created reponse with the Location of the new resource
+func CreatedResult(value interface{}, location string) *Result {
+	return OKResult(value).WithStatusCode(http.StatusCreated).WithHeader("Location", location)
+}
+
+//NoContentResult 204 No Content reponse
+func NoContentResult() *Result {
+	return OKResult(nil).WithStatusCode(http.StatusNoContent)
+}
+
+//WithStatusCode copy of the result answering the HTTP status when successful
+func (result *Result) WithStatusCode(statusCode int) *Result {
+	c := *result
+	c.Meta = result.Meta.Clone()
+	c.Meta.StatusCode = statusCode
+	return &c
+}
+
+//WithHeader copy of the result with the response header added
+func (result *Result) WithHeader(key, value string) *Result {
+	c := *result
+	c.Meta = result.Meta.Clone()
+	c.Meta.Header.Add(key, value)
+	return &c
+}
+
+//WithCookie copy of the result setting the cookie
+func (result *Result) WithCookie(cookie *http.Cookie) *Result {
+	c := *result
+	c.Meta = result.Meta.Clone()
+	c.Meta.Cookies = append(c.Meta.Cookies, cookie)
+	return &c
+}
+
 //ValidationErrorResult the first option param will be the code, the second one is the error message
 func ValidationErrorResult(err error, opts ...string) *Result {
 	errWrapper := newErrorResult(&ValidationError{}, err, opts)
@@ -64,31 +101,100 @@ func NotFoundErrorResult(err error, opts ...string) *Result {
 	}
 }
 
+//ConflictErrorResult the first option param will be the code, the second one is the error message
+func ConflictErrorResult(err error, opts ...string) *Result {
+	errWrapper := newErrorResult(&ConflictError{}, err, opts)
+	return &Result{
+		IsSuccess: false,
+		Value:     nil,
+		Error:     errWrapper,
+	}
+}
+
+//UnauthorizedErrorResult the first option param will be the code, the second one is the error message
+func UnauthorizedErrorResult(err error, opts ...string) *Result {
+	errWrapper := newErrorResult(&UnauthorizedError{}, err, opts)
+	return &Result{
+		IsSuccess: false,
+		Value:     nil,
+		Error:     errWrapper,
+	}
+}
+
+//ForbiddenErrorResult the first option param will be the code, the second one is the error message
+func ForbiddenErrorResult(err error, opts ...string) *Result {
+	errWrapper := newErrorResult(&ForbiddenError{}, err, opts)
+	return &Result{
+		IsSuccess: false,
+		Value:     nil,
+		Error:     errWrapper,
+	}
+}
+
+//PreconditionFailedErrorResult the first option param will be the code, the second one is the error message
+func PreconditionFailedErrorResult(err error, opts ...string) *Result {
+	errWrapper := newErrorResult(&PreconditionFailedError{}, err, opts)
+	return &Result{
+		IsSuccess: false,
+		Value:     nil,
+		Error:     errWrapper,
+	}
+}
+
+//TooManyRequestsErrorResult the first option param will be the code, the second one is the error message
+func TooManyRequestsErrorResult(err error, opts ...string) *Result {
+	errWrapper := newErrorResult(&TooManyRequestsError{}, err, opts)
+	return &Result{
+		IsSuccess: false,
+		Value:     nil,
+		Error:     errWrapper,
+	}
+}
+
+//UnavailableErrorResult the first option param will be the code, the second one is the error message
+func UnavailableErrorResult(err error, opts ...string) *Result {
+	errWrapper := newErrorResult(&UnavailableError{}, err, opts)
+	return &Result{
+		IsSuccess: false,
+		Value:     nil,
+		Error:     errWrapper,
+	}
+}
+
 //WriteResponse append response to request
 func (result *Result) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
 
 	if result.IsSuccess {
-		rw.WriteHeader(200)
-		if err := producer.Produce(rw, result.Value); err != nil {
-			panic(err)
-		}
+		infras.WriteValue(rw, producer, result.Meta, result.Value)
 	} else {
+		result.Meta.SetHeaders(rw)
 		err := result.Error
+		httpStatus := HTTPStatusOf(err)
+		message := err.GetErrorMessage()
+		if r := infras.RequestFromResponseWriter(rw); r != nil {
+			message = Localize(r.Context(), err)
+			httpStatus = infras.HTTPStatusFromContext(r.Context(), CodeOf(err))
+		}
+		message = redact.String(message)
+		violations := FieldViolationsOf(err)
 		responseMessage := &struct {
-			Message   string
-			ErrorCode string
+			Message         string
+			ErrorCode       string
+			FieldViolations []FieldViolation `json:",omitempty"`
 		}{
-			Message:   err.GetErrorMessage(),
-			ErrorCode: err.GetCode(),
+			Message:         message,
+			ErrorCode:       err.GetCode(),
+			FieldViolations: violations,
 		}
-		switch result.Error.(type) {
-		case *ValidationError:
-			rw.WriteHeader(400)
-		case *NotFoundError:
-			rw.WriteHeader(404)
-		default:
-			rw.WriteHeader(500)
+		if infras.AcceptsProblem(rw) {
+			problem := infras.NewProblemDetails(httpStatus, err.GetCode(), message)
+			if len(violations) > 0 {
+				problem.Extensions["fieldViolations"] = violations
+			}
+			infras.WriteProblem(rw, problem)
+			return
 		}
+		rw.WriteHeader(httpStatus)
 		if err := producer.Produce(rw, responseMessage); err != nil {
 			panic(err)
 		}
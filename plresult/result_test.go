@@ -1,10 +1,17 @@
 package plresult_test
 
 import (
+	"encoding/json"
 	"errors"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/go-openapi/runtime"
+	"github.com/jedrp/go-core/infras"
 	"github.com/jedrp/go-core/plresult"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestValidationErrorResult(t *testing.T) {
@@ -70,3 +77,160 @@ func TestSuccessResult(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteResponseProblem(t *testing.T) {
+	req := httptest.NewRequest("POST", "/users", nil)
+	req.Header.Set("Accept", "application/problem+json")
+
+	rec := httptest.NewRecorder()
+	result := plresult.ValidationErrorResult(errors.New("name is required"), "NAME_REQUIRED")
+	result.WriteResponse(infras.WithRequest(rec, req), runtime.JSONProducer())
+
+	if rec.Code != 400 {
+		t.Errorf("expected 400 but got %d", rec.Code)
+	}
+	var body map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if body["type"] != "urn:problem-type:name-required" || body["errorCode"] != "NAME_REQUIRED" || body["detail"] != "name is required" {
+		t.Errorf("unexpected problem %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	result.WriteResponse(rec, runtime.JSONProducer())
+	if rec.Header().Get("Content-Type") == infras.ProblemContentType {
+		t.Error("legacy clients should not get problem details")
+	}
+}
+
+func TestErrorKindsResponse(t *testing.T) {
+	tt := []struct {
+		Result       *plresult.Result
+		sentinel     error
+		expectedCode codes.Code
+		expectedHTTP int
+	}{
+		{plresult.ConflictErrorResult(errors.New("Jed_Test")), plresult.ErrConflict, codes.AlreadyExists, 409},
+		{plresult.UnauthorizedErrorResult(errors.New("Jed_Test")), plresult.ErrUnauthorized, codes.Unauthenticated, 401},
+		{plresult.ForbiddenErrorResult(errors.New("Jed_Test")), plresult.ErrForbidden, codes.PermissionDenied, 403},
+		{plresult.PreconditionFailedErrorResult(errors.New("Jed_Test")), plresult.ErrPreconditionFailed, codes.Aborted, 412},
+		{plresult.TooManyRequestsErrorResult(errors.New("Jed_Test")), plresult.ErrTooManyRequests, codes.ResourceExhausted, 429},
+		{plresult.UnavailableErrorResult(errors.New("Jed_Test")), plresult.ErrUnavailable, codes.Unavailable, 503},
+	}
+
+	for _, tc := range tt {
+		if !errors.Is(tc.Result.Error, tc.sentinel) {
+			t.Errorf("expected %T to match its sentinel", tc.Result.Error)
+		}
+		if code := plresult.CodeOf(tc.Result.Error); code != tc.expectedCode {
+			t.Errorf("expected %T code %v but got %v", tc.Result.Error, tc.expectedCode, code)
+		}
+		if code := status.Code(plresult.GetGrpcError(tc.Result.Error)); code != tc.expectedCode {
+			t.Errorf("expected %T gRPC code %v but got %v", tc.Result.Error, tc.expectedCode, code)
+		}
+		if e := plresult.NewErrorFromCode(tc.expectedCode, errors.New("Jed_Test")); !errors.Is(e, tc.sentinel) {
+			t.Errorf("expected code %v to create %T but got %T", tc.expectedCode, tc.Result.Error, e)
+		}
+		rec := httptest.NewRecorder()
+		tc.Result.WriteResponse(rec, runtime.JSONProducer())
+		if rec.Code != tc.expectedHTTP {
+			t.Errorf("expected %T to write %d but got %d", tc.Result.Error, tc.expectedHTTP, rec.Code)
+		}
+	}
+}
+
+func TestWriteResponseLocalized(t *testing.T) {
+	infras.Messages.Add("vi", map[string]string{"I18N_USER_NOT_FOUND": "không tìm thấy người dùng {id}"})
+	err := plresult.WithParams(plresult.NewNotFoundError(errors.New("no rows"), "I18N_USER_NOT_FOUND", "user not found"), map[string]string{"id": "7", "originError": "kept"})
+
+	req := httptest.NewRequest("GET", "/users/7", nil)
+	req = req.WithContext(infras.ContextWithLocale(req.Context(), "vi-VN"))
+	rec := httptest.NewRecorder()
+	(&plresult.Result{Error: err}).WriteResponse(infras.WithRequest(rec, req), runtime.JSONProducer())
+
+	var body map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if body["Message"] != "không tìm thấy người dùng 7" {
+		t.Errorf("unexpected body %s", rec.Body.String())
+	}
+	if err.GetErrorMessage() != "user not found" {
+		t.Errorf("the error should keep the canonical message but got %s", err.GetErrorMessage())
+	}
+
+	back := plresult.FromInfras(plresult.ToInfras(&plresult.Result{Error: err})).Error
+	if plresult.ParamsOf(back)["id"] != "7" || plresult.ParamsOf(back)["originError"] != "kept" || back.GetOriginError().Error() != "no rows" {
+		t.Errorf("expected params and origin to survive the conversion but got %v %v", plresult.ParamsOf(back), back.GetOriginError())
+	}
+}
+
+func TestToInfrasKeepOriginServerSide(t *testing.T) {
+	origin := errors.New("pq: connection to 10.0.0.5:5432 refused for user billing_rw")
+	r := plresult.ToInfras(plresult.InternalErrorResult(origin, "DB_DOWN", "internal error"))
+	if r.Cause != origin {
+		t.Errorf("expected the origin error as cause but got %v", r.Cause)
+	}
+
+	req := httptest.NewRequest("GET", "/invoices", nil)
+	req.Header.Set("Accept", "application/problem+json")
+	rec := httptest.NewRecorder()
+	r.WriteResponse(rec, runtime.JSONProducer())
+	problem := httptest.NewRecorder()
+	r.WriteResponse(infras.WithRequest(problem, req), runtime.JSONProducer())
+	for _, body := range []string{rec.Body.String(), problem.Body.String()} {
+		if strings.Contains(body, "10.0.0.5") {
+			t.Errorf("the origin error leaked to the client: %s", body)
+		}
+	}
+}
+
+func TestWriteResponseSameStatusAsInfras(t *testing.T) {
+	r := plresult.PreconditionFailedErrorResult(errors.New("order shipped"), "ORDER_SHIPPED", "order already shipped")
+	direct := httptest.NewRecorder()
+	r.WriteResponse(direct, runtime.JSONProducer())
+	converted := httptest.NewRecorder()
+	plresult.ToInfras(r).WriteResponse(converted, runtime.JSONProducer())
+	if direct.Code != converted.Code {
+		t.Errorf("expected the same status after the conversion but got %d and %d", direct.Code, converted.Code)
+	}
+
+	if direct.Code != 412 {
+		t.Errorf("expected 412 but got %d", direct.Code)
+	}
+
+	// services answering 409 override the code for both
+	req := httptest.NewRequest("PUT", "/orders/1", nil)
+	req = req.WithContext(infras.ContextWithHTTPStatus(req.Context(), infras.HTTPStatusOverrides{codes.Aborted: 409}))
+	direct = httptest.NewRecorder()
+	r.WriteResponse(infras.WithRequest(direct, req), runtime.JSONProducer())
+	converted = httptest.NewRecorder()
+	plresult.ToInfras(r).WriteResponse(infras.WithRequest(converted, req), runtime.JSONProducer())
+	if direct.Code != 409 || converted.Code != 409 {
+		t.Errorf("expected the overridden 409 but got %d and %d", direct.Code, converted.Code)
+	}
+}
+
+func TestWriteResponseMeta(t *testing.T) {
+	rec := httptest.NewRecorder()
+	plresult.CreatedResult(map[string]int{"id": 1}, "/users/1").WriteResponse(rec, runtime.JSONProducer())
+	if rec.Code != 201 || rec.Header().Get("Location") != "/users/1" {
+		t.Errorf("unexpected response %d %v", rec.Code, rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	plresult.NoContentResult().WriteResponse(rec, runtime.JSONProducer())
+	if rec.Code != 204 || rec.Body.Len() != 0 {
+		t.Errorf("expected empty 204 but got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	stream := &infras.NDJSONStream{Each: func(emit func(item interface{}) error) error {
+		return emit("a")
+	}}
+	plresult.OKResult(stream).WithHeader("X-Total-Count", "1").WriteResponse(rec, runtime.JSONProducer())
+	if rec.Body.String() != "\"a\"\n" || rec.Header().Get("X-Total-Count") != "1" {
+		t.Errorf("unexpected stream %v %q", rec.Header(), rec.Body.String())
+	}
+
+	if r := plresult.FromInfras(plresult.ToInfras(plresult.CreatedResult(1, "/users/1"))); r.Meta == nil || r.Meta.StatusCode != 201 {
+		t.Errorf("expected the metadata to survive the conversion but got %v", r.Meta)
+	}
+}
@@ -0,0 +1,125 @@
+package plresult
+
+import (
+	"fmt"
+
+	"github.com/jedrp/go-core/infras"
+	"google.golang.org/grpc/codes"
+)
+
+// StatusError error of a canonical code without dedicated error kind
+type StatusError struct {
+	OriginError  error             //the origin error causing problem
+	ErrorCode    string            //System specific error code
+	ErrorMessage string            //System specfic error message
+	Code         codes.Code        //canonical gRPC code
+	Params       map[string]string //message catalog template params
+	stack        []uintptr
+}
+
+func (err *StatusError) GetCode() string {
+	return err.ErrorCode
+}
+
+func (err *StatusError) GetOriginError() error {
+	return err.OriginError
+}
+
+func (err *StatusError) GetErrorMessage() string {
+	return err.ErrorMessage
+}
+
+func (err *StatusError) SetCode(code string) {
+	err.ErrorCode = code
+}
+
+func (err *StatusError) SetError(orgError error) {
+	err.OriginError = orgError
+}
+
+func (err *StatusError) SetMessage(msg string) {
+	err.ErrorMessage = msg
+}
+
+func (err *StatusError) Error() string {
+	return errorString(err.ErrorMessage, err.OriginError)
+}
+
+func (err *StatusError) Unwrap() error {
+	return err.OriginError
+}
+
+// Is match StatusError sentinels of the same canonical code
+func (err *StatusError) Is(target error) bool {
+	t, ok := target.(*StatusError)
+	return ok && t.Code == err.Code && matchCode(t.ErrorCode, err.ErrorCode)
+}
+
+func (err *StatusError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, err.ErrorCode, err.ErrorMessage, err.OriginError, err.stack)
+}
+
+func (err *StatusError) setStack(stack []uintptr) {
+	err.stack = stack
+}
+
+func (err *StatusError) params() *map[string]string {
+	return &err.Params
+}
+
+// errorKinds the error taxonomy, dedicated kind of canonical codes, other codes use StatusError.
+// The HTTP status is the one of the code in infras, services answering otherwise use infras.HTTPStatusOverrides
+var errorKinds = []struct {
+	code codes.Code
+	new  func() Error
+	is   func(Error) bool
+}{
+	{codes.InvalidArgument, func() Error { return &ValidationError{} }, func(e Error) bool { _, ok := e.(*ValidationError); return ok }},
+	{codes.NotFound, func() Error { return &NotFoundError{} }, func(e Error) bool { _, ok := e.(*NotFoundError); return ok }},
+	{codes.Internal, func() Error { return &InternalServerError{} }, func(e Error) bool { _, ok := e.(*InternalServerError); return ok }},
+	{codes.Unknown, func() Error { return &UnkownError{} }, func(e Error) bool { _, ok := e.(*UnkownError); return ok }},
+	{codes.AlreadyExists, func() Error { return &ConflictError{} }, func(e Error) bool { _, ok := e.(*ConflictError); return ok }},
+	{codes.Unauthenticated, func() Error { return &UnauthorizedError{} }, func(e Error) bool { _, ok := e.(*UnauthorizedError); return ok }},
+	{codes.PermissionDenied, func() Error { return &ForbiddenError{} }, func(e Error) bool { _, ok := e.(*ForbiddenError); return ok }},
+	// Aborted answers 412 Precondition Failed in infras, FailedPrecondition is 400
+	{codes.Aborted, func() Error { return &PreconditionFailedError{} }, func(e Error) bool { _, ok := e.(*PreconditionFailedError); return ok }},
+	{codes.ResourceExhausted, func() Error { return &TooManyRequestsError{} }, func(e Error) bool { _, ok := e.(*TooManyRequestsError); return ok }},
+	{codes.Unavailable, func() Error { return &UnavailableError{} }, func(e Error) bool { _, ok := e.(*UnavailableError); return ok }},
+}
+
+// CodeOf canonical gRPC code of the error kind, errors unknown to the taxonomy are Internal
+func CodeOf(e Error) codes.Code {
+	if s, ok := e.(*StatusError); ok {
+		return s.Code
+	}
+	for _, k := range errorKinds {
+		if k.is(e) {
+			return k.code
+		}
+	}
+	return codes.Internal
+}
+
+// HTTPStatusOf HTTP status of the error kind, the same as infras.Result failing with its code
+func HTTPStatusOf(e Error) int {
+	return infras.HTTPStatusFromCode(CodeOf(e))
+}
+
+// NewErrorFromCode error of the kind of the code, the first option param will be the code, the second one is the error message
+func NewErrorFromCode(code codes.Code, err error, opts ...string) Error {
+	for _, k := range errorKinds {
+		if k.code == code {
+			return newErrorResult(k.new(), err, opts)
+		}
+	}
+	return newErrorResult(&StatusError{Code: code}, err, opts)
+}
+
+// ErrorResult failed result of the kind of the code, the first option param will be the code, the second one is the error message
+func ErrorResult(code codes.Code, err error, opts ...string) *Result {
+	return &Result{
+		IsSuccess: false,
+		Value:     nil,
+		Error:     NewErrorFromCode(code, err, opts...),
+	}
+}
@@ -0,0 +1,48 @@
+package plresult
+
+import (
+	"errors"
+
+	"github.com/jedrp/go-core/infras"
+)
+
+// FieldViolation invalid field of a request, see infras.FieldViolation
+type FieldViolation = infras.FieldViolation
+
+// ValidationErrors accumulate the field violations of a request, used as the origin error of a ValidationError:
+//
+//	v := &plresult.ValidationErrors{}
+//	v.Add("email", "INVALID_EMAIL", "email is not valid")
+//	if v.HasViolations() {
+//		return v.Result("INVALID_USER", "invalid user")
+//	}
+type ValidationErrors infras.ValidationErrors
+
+// Add a violation of the field
+func (v *ValidationErrors) Add(path, code, message string) *ValidationErrors {
+	(*infras.ValidationErrors)(v).Add(path, code, message)
+	return v
+}
+
+// HasViolations true when at least one violation was added
+func (v *ValidationErrors) HasViolations() bool {
+	return (*infras.ValidationErrors)(v).HasViolations()
+}
+
+func (v *ValidationErrors) Error() string {
+	return (*infras.ValidationErrors)(v).Error()
+}
+
+// Result ValidationErrorResult of the violations, the first option param will be the code, the second one is the error message
+func (v *ValidationErrors) Result(opts ...string) *Result {
+	return ValidationErrorResult(v, opts...)
+}
+
+// FieldViolationsOf violations of the ValidationErrors in the origin error chain
+func FieldViolationsOf(e Error) []FieldViolation {
+	var v *ValidationErrors
+	if errors.As(e, &v) {
+		return v.Violations
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package plresult_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/jedrp/go-core/infras"
+	"github.com/jedrp/go-core/plresult"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidationErrors(t *testing.T) {
+	v := &plresult.ValidationErrors{}
+	v.Add("name", "REQUIRED", "name is required").
+		Add("email", "INVALID_EMAIL", "email is not valid").
+		Add("items[2].quantity", "OUT_OF_RANGE", "quantity must be positive")
+	result := v.Result("INVALID_ORDER", "invalid order")
+
+	if !errors.Is(result.Error, plresult.ErrValidation) {
+		t.Errorf("expected a ValidationError but got %T", result.Error)
+	}
+
+	rec := httptest.NewRecorder()
+	result.WriteResponse(rec, runtime.JSONProducer())
+	var body struct {
+		Message         string
+		ErrorCode       string
+		FieldViolations []plresult.FieldViolation
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != 400 || body.ErrorCode != "INVALID_ORDER" || len(body.FieldViolations) != 3 || body.FieldViolations[2].Code != "OUT_OF_RANGE" {
+		t.Errorf("unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+
+	req := httptest.NewRequest("POST", "/orders", nil)
+	req.Header.Set("Accept", "application/problem+json")
+	rec = httptest.NewRecorder()
+	result.WriteResponse(infras.WithRequest(rec, req), runtime.JSONProducer())
+	var problem map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &problem)
+	if violations, _ := problem["fieldViolations"].([]interface{}); len(violations) != 3 {
+		t.Errorf("expected the violations in the problem but got %s", rec.Body.String())
+	}
+
+	s := status.Convert(plresult.GetGrpcError(result.Error))
+	var br *errdetails.BadRequest
+	for _, d := range s.Details() {
+		if b, ok := d.(*errdetails.BadRequest); ok {
+			br = b
+		}
+	}
+	if br == nil || len(br.FieldViolations) != 3 || br.FieldViolations[1].Field != "email" || br.FieldViolations[1].Description != "email is not valid" {
+		t.Errorf("expected BadRequest field violations but got %v", s.Details())
+	}
+
+	back := plresult.FieldViolationsOf(plresult.FromGrpcError(s.Err()))
+	if len(back) != 3 || back[2].Path != "items[2].quantity" {
+		t.Errorf("expected violations back from gRPC but got %v", back)
+	}
+}
@@ -0,0 +1,303 @@
+// Package redact mask sensitive data before it leaves the process: log entries, audit payloads,
+// error messages returned to clients and protobuf Struct values.
+//
+// Struct fields tagged `redact:"true"` are always masked, fields named as one of the redacted
+// field names (compared case insensitive) are masked in maps and structs, and strings are
+// rewritten by the regex rules (emails, bearer tokens, JWTs and card numbers by default).
+package redact
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mask replacement of redacted values
+const Mask = "[REDACTED]"
+
+const maxDepth = 32
+
+// DefaultFields field names always masked by Default
+var DefaultFields = []string{"password", "secret", "token", "accessToken", "refreshToken", "authorization", "apiKey", "creditCard", "cardNumber", "cvv"}
+
+// DefaultRules regex rules of Default
+var DefaultRules = []Rule{
+	{Name: "email", Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	{Name: "bearer", Pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`), Replacement: "Bearer " + Mask},
+	{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)},
+	{Name: "card", Pattern: regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`), Valid: luhn},
+}
+
+// Default redactor used by pllog, infras, plresult, cqrs and the cqs audit
+var Default = New(DefaultRules...).WithFields(DefaultFields...)
+
+var (
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Rule replace the matches of Pattern by Replacement, Mask when empty.
+// Valid filters out false positives of the pattern when set (Luhn checksum of card numbers)
+type Rule struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Replacement string
+	Valid       func(match string) bool
+}
+
+// Redactor masks struct fields, named fields and the regex matches of strings, safe for concurrent use
+type Redactor struct {
+	mu     sync.RWMutex
+	rules  []Rule
+	fields map[string]bool
+}
+
+// New redactor of the rules
+func New(rules ...Rule) *Redactor {
+	return &Redactor{rules: rules, fields: map[string]bool{}}
+}
+
+// AddRule add a regex rule, e.g. AddRule("iban", `\b[A-Z]{2}\d{2}[A-Z0-9]{11,30}\b`)
+func (r *Redactor) AddRule(name, pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = append(r.rules, Rule{Name: name, Pattern: re})
+	return nil
+}
+
+// AddFields add names of fields masked in maps and structs
+func (r *Redactor) AddFields(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		r.fields[fieldKey(name)] = true
+	}
+}
+
+// WithFields copy of the redactor masking the fields as well
+func (r *Redactor) WithFields(names ...string) *Redactor {
+	r.mu.RLock()
+	c := &Redactor{rules: append([]Rule(nil), r.rules...), fields: make(map[string]bool, len(r.fields)+len(names))}
+	for k := range r.fields {
+		c.fields[k] = true
+	}
+	r.mu.RUnlock()
+	c.AddFields(names...)
+	return c
+}
+
+// IsField true when values of the field name are masked
+func (r *Redactor) IsField(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.fields[fieldKey(name)]
+}
+
+// IsStructField true when values of the struct field are masked: tagged `redact:"true"`, or its
+// Go or JSON name is a redacted field name
+func (r *Redactor) IsStructField(f reflect.StructField) bool {
+	if IsTagged(f) || r.IsField(f.Name) {
+		return true
+	}
+	name, _ := jsonName(f)
+	return name != "" && r.IsField(name)
+}
+
+// String s with the regex matches replaced
+func (r *Redactor) String(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, rule := range r.rules {
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = Mask
+		}
+		if rule.Valid == nil {
+			s = rule.Pattern.ReplaceAllLiteralString(s, replacement)
+			continue
+		}
+		valid := rule.Valid
+		s = rule.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if valid(match) {
+				return replacement
+			}
+			return match
+		})
+	}
+	return s
+}
+
+// Fields copy of log fields with the values redacted
+func (r *Redactor) Fields(fields map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if r.IsField(k) {
+			redacted[k] = Mask
+		} else {
+			redacted[k] = r.Value(v)
+		}
+	}
+	return redacted
+}
+
+// Value redacted copy of v, structs become maps keyed by their JSON names and slices []interface{},
+// values of other kinds and types with their own JSON or text encoding are kept
+func (r *Redactor) Value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return r.String(v)
+	case error:
+		return r.String(v.Error())
+	}
+	return r.value(reflect.ValueOf(v), 0)
+}
+
+func (r *Redactor) value(v reflect.Value, depth int) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	if v.Type().Implements(errorType) && (v.Kind() != reflect.Ptr || !v.IsNil()) {
+		return r.String(v.Interface().(error).Error())
+	}
+	if depth > maxDepth || v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return r.value(v.Elem(), depth+1)
+	case reflect.String:
+		return r.String(v.String())
+	case reflect.Struct:
+		m := map[string]interface{}{}
+		r.structFields(m, v, depth)
+		return m
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		m := make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			if r.IsField(k.String()) {
+				m[k.String()] = Mask
+			} else {
+				m[k.String()] = r.value(v.MapIndex(k), depth+1)
+			}
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			return v.Interface()
+		}
+		s := make([]interface{}, v.Len())
+		for i := range s {
+			s[i] = r.value(v.Index(i), depth+1)
+		}
+		return s
+	default:
+		return v.Interface()
+	}
+}
+
+// structFields add the exported fields of the struct, embedded structs without JSON name are flattened as encoding/json does
+func (r *Redactor) structFields(m map[string]interface{}, v reflect.Value, depth int) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, skip := jsonName(f)
+		if skip {
+			continue
+		}
+		fv := v.Field(i)
+		if f.Anonymous && name == "" {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				r.structFields(m, fv, depth+1)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if r.IsStructField(f) {
+			m[name] = Mask
+		} else {
+			m[name] = r.value(fv, depth+1)
+		}
+	}
+}
+
+// IsTagged true for fields tagged `redact:"true"`
+func IsTagged(f reflect.StructField) bool {
+	return f.Tag.Get("redact") == "true"
+}
+
+// String s redacted by Default
+func String(s string) string {
+	return Default.String(s)
+}
+
+// Value v redacted by Default
+func Value(v interface{}) interface{} {
+	return Default.Value(v)
+}
+
+// Fields log fields redacted by Default
+func Fields(fields map[string]interface{}) map[string]interface{} {
+	return Default.Fields(fields)
+}
+
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name := strings.Split(tag, ",")[0]
+	return name, false
+}
+
+// fieldKey field names compared case insensitive, ignoring _ and -
+func fieldKey(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
+
+// luhn checksum of the digits of a card number
+func luhn(number string) bool {
+	sum, n := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
@@ -0,0 +1,94 @@
+package redact_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jedrp/go-core/redact"
+)
+
+type address struct {
+	Street string `json:"street" redact:"true"`
+	City   string `json:"city"`
+}
+
+type Audit struct {
+	By string `json:"by"`
+}
+
+type signUp struct {
+	Audit
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Password string    `json:"password"`
+	SSN      string    `json:"ssn" redact:"true"`
+	Address  *address  `json:"address"`
+	Tags     []string  `json:"tags"`
+	At       time.Time `json:"at"`
+	Ignored  string    `json:"-"`
+	internal string
+}
+
+func TestString(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected string
+	}{
+		{"user jed@example.com not found", "user [REDACTED] not found"},
+		{"Authorization: Bearer abc.def-123", "Authorization: Bearer [REDACTED]"},
+		{"token eyJhbGciOi.eyJzdWIiOi.c2lnbmF0dXJl expired", "token [REDACTED] expired"},
+		{"card 4111 1111 1111 1111 declined", "card [REDACTED] declined"},
+		{"order 1234567890123456 failed", "order 1234567890123456 failed"},
+		{"nothing to hide", "nothing to hide"},
+	}
+	for _, tc := range tt {
+		if s := redact.String(tc.input); s != tc.expected {
+			t.Errorf("expected %q but got %q", tc.expected, s)
+		}
+	}
+}
+
+func TestValue(t *testing.T) {
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	v := redact.Value(&signUp{
+		Audit:    Audit{By: "admin"},
+		Name:     "jed",
+		Email:    "jed@example.com",
+		Password: "p4ss",
+		SSN:      "123-45-6789",
+		Address:  &address{Street: "1 main st", City: "Hanoi"},
+		Tags:     []string{"contact jed@example.com"},
+		At:       at,
+		Ignored:  "x",
+		internal: "y",
+	})
+	b, _ := json.Marshal(v)
+	expected := `{"address":{"city":"Hanoi","street":"[REDACTED]"},"at":"2020-01-02T03:04:05Z","by":"admin","email":"[REDACTED]","name":"jed","password":"[REDACTED]","ssn":"[REDACTED]","tags":["contact [REDACTED]"]}`
+	if string(b) != expected {
+		t.Errorf("expected %s but got %s", expected, b)
+	}
+
+	fields := redact.Fields(map[string]interface{}{"api_key": "k", "error": errors.New("no user jed@example.com"), "count": 2})
+	if fields["api_key"] != redact.Mask || fields["error"] != "no user [REDACTED]" || fields["count"] != 2 {
+		t.Errorf("unexpected fields %v", fields)
+	}
+}
+
+func TestRedactorRules(t *testing.T) {
+	r := redact.New()
+	if err := r.AddRule("iban", `\bVN\d{2}[A-Z0-9]{10,30}\b`); err != nil {
+		t.Fatal(err)
+	}
+	if s := r.String("pay to VN12ABCD1234567890 jed@example.com"); s != "pay to [REDACTED] jed@example.com" {
+		t.Errorf("unexpected %q", s)
+	}
+	if err := r.AddRule("bad", `(`); err == nil {
+		t.Error("expected invalid pattern to fail")
+	}
+	derived := r.WithFields("pin")
+	if r.IsField("pin") || !derived.IsField("PIN") {
+		t.Error("WithFields should not change the original redactor")
+	}
+}
@@ -0,0 +1,97 @@
+package result
+
+import (
+	"github.com/jedrp/go-core/infras"
+	"github.com/jedrp/go-core/plresult"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Result typed counterpart of infras.Result, Value is the zero value of T when Error is set
+type Result[T any] struct {
+	Value T
+	Error *status.Status
+}
+
+func OK[T any](v T) *Result[T] {
+	return &Result[T]{
+		Value: v,
+	}
+}
+
+func Fail[T any](code codes.Code, m string) *Result[T] {
+	return &Result[T]{
+		Error: status.New(code, m),
+	}
+}
+
+func Failf[T any](code codes.Code, f string, o ...interface{}) *Result[T] {
+	return &Result[T]{
+		Error: status.Newf(code, f, o...),
+	}
+}
+
+// IsSuccess true when the result has no error
+func (r *Result[T]) IsSuccess() bool {
+	return r.Error == nil
+}
+
+// Unwrap return the value, or the gRPC error of the failure
+func (r *Result[T]) Unwrap() (T, error) {
+	if r.Error != nil {
+		var zero T
+		return zero, r.Error.Err()
+	}
+	return r.Value, nil
+}
+
+// Map transform the value of a successful result, failures are propagated
+func Map[T, U any](r *Result[T], f func(T) U) *Result[U] {
+	if r.Error != nil {
+		return &Result[U]{Error: r.Error}
+	}
+	return OK(f(r.Value))
+}
+
+// FlatMap chain an operation that can fail on a successful result, failures are propagated
+func FlatMap[T, U any](r *Result[T], f func(T) *Result[U]) *Result[U] {
+	if r.Error != nil {
+		return &Result[U]{Error: r.Error}
+	}
+	return f(r.Value)
+}
+
+// ToInfras untyped result returned by cqs executors
+func (r *Result[T]) ToInfras() *infras.Result {
+	if r.Error != nil {
+		return &infras.Result{Error: r.Error}
+	}
+	return infras.OK(r.Value)
+}
+
+// FromInfras typed result of an untyped one, a value of another type than T become an Internal failure
+func FromInfras[T any](r *infras.Result) *Result[T] {
+	if r.Error != nil {
+		return &Result[T]{Error: r.Error}
+	}
+	if r.Value == nil {
+		var zero T
+		return OK(zero)
+	}
+	v, ok := r.Value.(T)
+	if !ok {
+		var zero T
+		return Failf[T](codes.Internal, "result value of type %T is not %T", r.Value, zero)
+	}
+	return OK(v)
+}
+
+// ToPlresult untyped result returned by cqrs handlers
+func (r *Result[T]) ToPlresult() *plresult.Result {
+	return plresult.FromInfras(r.ToInfras())
+}
+
+// FromPlresult typed result of a cqrs handler result, a value of another type than T become an Internal failure
+func FromPlresult[T any](r *plresult.Result) *Result[T] {
+	return FromInfras[T](plresult.ToInfras(r))
+}
@@ -0,0 +1,62 @@
+package result_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/jedrp/go-core/infras"
+	"github.com/jedrp/go-core/plresult"
+	"github.com/jedrp/go-core/result"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMapAndFlatMap(t *testing.T) {
+	r := result.Map(result.OK(42), strconv.Itoa)
+	if v, err := r.Unwrap(); err != nil || v != "42" {
+		t.Errorf("expected 42 but got %v %v", v, err)
+	}
+
+	parse := func(s string) *result.Result[int] {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return result.Fail[int](codes.InvalidArgument, err.Error())
+		}
+		return result.OK(i)
+	}
+	if v, _ := result.FlatMap(result.OK("7"), parse).Unwrap(); v != 7 {
+		t.Errorf("expected 7 but got %v", v)
+	}
+	failed := result.FlatMap(result.OK("seven"), parse)
+	if _, err := failed.Unwrap(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument but got %v", err)
+	}
+	// failures skip the mapping
+	called := false
+	result.Map(failed, func(int) int { called = true; return 0 })
+	if called {
+		t.Error("map should not be called on failure")
+	}
+}
+
+func TestConversions(t *testing.T) {
+	if v, err := result.FromInfras[int](infras.OK(1)).Unwrap(); err != nil || v != 1 {
+		t.Errorf("expected 1 but got %v %v", v, err)
+	}
+	if _, err := result.FromInfras[string](infras.OK(1)).Unwrap(); status.Code(err) != codes.Internal {
+		t.Errorf("expected Internal on type mismatch but got %v", err)
+	}
+	if r := result.OK("a").ToInfras(); r.Value != "a" || r.Error != nil {
+		t.Errorf("unexpected infras result %+v", r)
+	}
+
+	r := result.FromPlresult[int](plresult.NotFoundErrorResult(errors.New("missing")))
+	if _, err := r.Unwrap(); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound but got %v", err)
+	}
+	pr := result.Fail[int](codes.InvalidArgument, "bad").ToPlresult()
+	if _, ok := pr.Error.(*plresult.ValidationError); !ok || pr.Error.GetErrorMessage() != "bad" {
+		t.Errorf("expected validation error but got %+v", pr.Error)
+	}
+}